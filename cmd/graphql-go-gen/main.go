@@ -0,0 +1,705 @@
+// Command graphql-go-gen generates a schema from SDL files and validates, prints,
+// introspects or turns it into Go code.
+//
+// Usage:
+//
+//	graphql-go-gen <command> [flags] [files...]
+//
+// The commands are:
+//
+//	validate    generate the schema and report its errors
+//	print       print the normalized SDL
+//	introspect  print the introspection result as JSON
+//	codegen     emit Go code
+//	diff        compare an old and a new schema and report the changes
+//	changelog   write a Markdown changelog of schema releases
+//	docs        write Markdown or HTML documentation pages to a directory
+//	graph       draw the types as Graphviz DOT or Mermaid diagram
+//	unused      list the types unreachable from the operation types
+//	lint        check naming, description and structure conventions
+//	fmt         reformat SDL files canonically
+//	lsp         run a language server for SDL files over stdio
+//
+// Files may be glob patterns. Each file is parsed separately and errors are reported
+// with the file they originate from. If no files are given the SDL is read from stdin.
+// The command exits with 1 if the schema has errors and with 2 on usage or I/O errors,
+// so it can be used from go:generate directives and CI pipelines:
+//
+//	//go:generate graphql-go-gen codegen -package api -o schema_gen.go schema.graphql
+//
+// diff exits with 1 on breaking changes, or on the changes selected by -fail-on, so
+// that CI catches them before clients break:
+//
+//	graphql-go-gen diff old.graphql schema.graphql
+//
+// changelog takes the releases from the oldest to the newest as name=file arguments,
+// e.g. with the SDL of earlier revisions exported by git show:
+//
+//	graphql-go-gen changelog v1.0=v1.graphql v1.1=v1.1.graphql v2.0=schema.graphql
+//
+// validate, print, introspect, codegen, docs, graph, lint and unused take a -watch
+// flag, which keeps running the command whenever the files change until interrupted:
+//
+//	graphql-go-gen codegen -watch -package api -o schema_gen.go schema.graphql
+//
+// docs writes one page per type and an index page to the directory given by -o:
+//
+//	graphql-go-gen docs -format html -o site schema.graphql
+//
+// graph may be limited to the types around one type, e.g. to render them with dot:
+//
+//	graphql-go-gen graph -root User -depth 2 schema.graphql | dot -Tsvg > user.svg
+//
+// lint exits with 1 if a rule at level error is violated. The levels of the rules,
+// listed by -list, are set by -rule:
+//
+//	graphql-go-gen lint -rule descriptions=error -rule input-names=off schema.graphql
+//
+// fmt prints the formatted SDL, rewrites the files with -w or lists the files whose
+// formatting differs with -l, exiting with 1 if there are any:
+//
+//	graphql-go-gen fmt -w 'schema/*.graphql'
+//
+// lsp serves the Language Server Protocol on stdin and stdout for editors. It loads
+// the .graphql, .graphqls and .gql files of the workspace folder.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alpox/graphql-go-gen/generator"
+	"github.com/alpox/graphql-go-gen/lsp"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+const (
+	exitOK     = 0
+	exitSchema = 1
+	exitUsage  = 2
+)
+
+const usage = `usage: graphql-go-gen <command> [flags] [files...]
+
+The commands are:
+
+	validate    generate the schema and report its errors
+	print       print the normalized SDL
+	introspect  print the introspection result as JSON
+	codegen     emit Go code
+	diff        compare an old and a new schema and report the changes
+	changelog   write a Markdown changelog of schema releases
+	docs        write Markdown or HTML documentation pages to a directory
+	graph       draw the types as Graphviz DOT or Mermaid diagram
+	unused      list the types unreachable from the operation types
+	lint        check naming, description and structure conventions
+	fmt         reformat SDL files canonically
+	lsp         run a language server for SDL files over stdio
+
+Run 'graphql-go-gen <command> -h' for the flags of a command.
+`
+
+// command holds the state of one invocation of a subcommand.
+type command struct {
+	name   string
+	flags  *flag.FlagSet
+	output string
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// watchable are the commands taking the -watch flag.
+var watchable = map[string]bool{
+	"validate":   true,
+	"print":      true,
+	"introspect": true,
+	"codegen":    true,
+	"docs":       true,
+	"graph":      true,
+	"lint":       true,
+	"unused":     true,
+}
+
+// watchContext returns the context -watch runs in, watchInterval is the interval
+// it checks the files for changes in and watchRan is called after each run.
+var (
+	watchContext = func() (context.Context, context.CancelFunc) {
+		return signal.NotifyContext(context.Background(), os.Interrupt)
+	}
+	watchInterval = 500 * time.Millisecond
+	watchRan      = func() {}
+)
+
+// subcommand registers its flags on cmd and returns the function running it.
+type subcommand func(cmd *command) func(files []string) error
+
+var commands = map[string]subcommand{
+	"validate":   validateCommand,
+	"print":      printCommand,
+	"introspect": introspectCommand,
+	"codegen":    codegenCommand,
+	"diff":       diffCommand,
+	"changelog":  changelogCommand,
+	"docs":       docsCommand,
+	"graph":      graphCommand,
+	"unused":     unusedCommand,
+	"lint":       lintCommand,
+	"fmt":        fmtCommand,
+	"lsp":        lspCommand,
+}
+
+// usageError marks errors caused by the invocation rather than by the schema.
+type usageError struct {
+	err error
+}
+
+func (e usageError) Error() string {
+	return "graphql-go-gen: " + e.err.Error()
+}
+
+// schemaErrors joins errors to one error with one error per line.
+type schemaErrors []error
+
+func (errs schemaErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+	newCommand, ok := commands[args[0]]
+	if !ok {
+		if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+			fmt.Fprint(stdout, usage)
+			return exitOK
+		}
+		fmt.Fprintf(stderr, "graphql-go-gen: unknown command %q\n\n%s", args[0], usage)
+		return exitUsage
+	}
+
+	cmd := &command{
+		name:   args[0],
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+	}
+	cmd.flags = flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	cmd.flags.SetOutput(stderr)
+	cmd.flags.StringVar(&cmd.output, "o", "", "write the output to `file` instead of stdout")
+	var watch bool
+	if watchable[cmd.name] {
+		cmd.flags.BoolVar(&watch, "watch", false, "run the command again whenever the files change")
+	}
+	runFn := newCommand(cmd)
+	if err := cmd.flags.Parse(args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	if watch {
+		runFn = cmd.watch(runFn)
+	}
+	if err := runFn(cmd.flags.Args()); err != nil {
+		fmt.Fprintln(stderr, err)
+		var usageErr usageError
+		if errors.As(err, &usageErr) {
+			return exitUsage
+		}
+		return exitSchema
+	}
+	return exitOK
+}
+
+// watch returns a function running runFn on the files and again whenever they
+// change until interrupted.
+func (cmd *command) watch(runFn func(files []string) error) func(files []string) error {
+	return func(files []string) error {
+		if len(files) == 0 {
+			return usageError{errors.New("-watch expects files")}
+		}
+		for _, pattern := range files {
+			if !fs.ValidPath(filepath.ToSlash(pattern)) {
+				return usageError{fmt.Errorf("-watch expects paths relative to the working directory, got %s", pattern)}
+			}
+		}
+
+		watcher := generator.NewFileWatcher(os.DirFS("."), files...)
+		runOnce := func() {
+			if err := runFn(files); err != nil {
+				fmt.Fprintln(cmd.stderr, err)
+			}
+			watchRan()
+		}
+		ctx, stop := watchContext()
+		defer stop()
+		ticker := time.NewTicker(watchInterval)
+		defer ticker.Stop()
+
+		runOnce()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+			if watcher.Changed() {
+				runOnce()
+			}
+		}
+	}
+}
+
+// read returns a source per file or the source read from stdin if no files are given.
+// Files may be glob patterns.
+func (cmd *command) read(files []string) ([]*source.Source, error) {
+	if len(files) == 0 {
+		body, err := io.ReadAll(cmd.stdin)
+		if err != nil {
+			return nil, usageError{err}
+		}
+		return []*source.Source{source.NewSource(&source.Source{Body: body})}, nil
+	}
+
+	var sources []*source.Source
+	for _, pattern := range files {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, usageError{err}
+		}
+		if len(matches) == 0 {
+			return nil, usageError{fmt.Errorf("no files match %s", pattern)}
+		}
+		for _, file := range matches {
+			body, err := os.ReadFile(file)
+			if err != nil {
+				return nil, usageError{err}
+			}
+			sources = append(sources, source.NewSource(&source.Source{Body: body, Name: file}))
+		}
+	}
+	return sources, nil
+}
+
+// generate generates a context from files.
+func (cmd *command) generate(files []string) (*generator.Context, error) {
+	sources, err := cmd.read(files)
+	if err != nil {
+		return nil, err
+	}
+	return generator.GenerateSources(sources...)
+}
+
+// load generates a context from files and fails if any definition could not be generated.
+func (cmd *command) load(files []string) (*generator.Context, error) {
+	ctx, err := cmd.generate(files)
+	if err != nil {
+		return nil, err
+	}
+	if errs := ctx.Errors(); len(errs) > 0 {
+		return nil, schemaErrors(errs)
+	}
+	return ctx, nil
+}
+
+// write writes out to the output file or stdout.
+func (cmd *command) write(out []byte) error {
+	if cmd.output == "" {
+		if _, err := cmd.stdout.Write(out); err != nil {
+			return usageError{err}
+		}
+		return nil
+	}
+	if err := os.WriteFile(cmd.output, out, 0644); err != nil {
+		return usageError{err}
+	}
+	return nil
+}
+
+func validateCommand(cmd *command) func(files []string) error {
+	return func(files []string) error {
+		ctx, err := cmd.generate(files)
+		if err != nil {
+			return err
+		}
+		if errs := ctx.Validate(); len(errs) > 0 {
+			return schemaErrors(errs)
+		}
+		return nil
+	}
+}
+
+func printCommand(cmd *command) func(files []string) error {
+	prune := cmd.flags.Bool("prune", false, "leave out the types unreachable from the operation types")
+
+	return func(files []string) error {
+		ctx, err := cmd.load(files)
+		if err != nil {
+			return err
+		}
+		if *prune {
+			ctx.Prune()
+		}
+		return cmd.write([]byte(generator.PrintSchema(ctx)))
+	}
+}
+
+func introspectCommand(cmd *command) func(files []string) error {
+	return func(files []string) error {
+		ctx, err := cmd.load(files)
+		if err != nil {
+			return err
+		}
+		result, err := generator.Introspect(ctx)
+		if err != nil {
+			return err
+		}
+		out, err := json.MarshalIndent(result.Data, "", "  ")
+		if err != nil {
+			return err
+		}
+		return cmd.write(append(out, '\n'))
+	}
+}
+
+// mappingFlag collects repeated name=value flags.
+type mappingFlag map[string]string
+
+func (m mappingFlag) String() string {
+	var mappings []string
+	for name, value := range m {
+		mappings = append(mappings, name+"="+value)
+	}
+	return strings.Join(mappings, ",")
+}
+
+func (m mappingFlag) Set(mapping string) error {
+	name, value, ok := strings.Cut(mapping, "=")
+	if !ok || name == "" || value == "" {
+		return fmt.Errorf("expected name=value, got %q", mapping)
+	}
+	m[name] = value
+	return nil
+}
+
+func codegenCommand(cmd *command) func(files []string) error {
+	pkg := cmd.flags.String("package", "schema", "package `name` of the generated code")
+	models := cmd.flags.Bool("models", false, "emit model structs, enum constants and argument decoders")
+	resolvers := cmd.flags.Bool("resolvers", false, "emit resolver interfaces and a Bind function (implies -models)")
+	stubs := cmd.flags.Bool("stubs", false, "emit editable stub implementations of the root resolvers instead")
+	scalars := mappingFlag{}
+	cmd.flags.Var(scalars, "scalar", "map a custom scalar to a Go type in the models, e.g. `Time=time.Time` (repeatable)")
+
+	return func(files []string) error {
+		ctx, err := cmd.load(files)
+		if err != nil {
+			return err
+		}
+		config := generator.CodegenConfig{
+			Package:   *pkg,
+			Models:    *models,
+			Resolvers: *resolvers,
+			Scalars:   scalars,
+		}
+		codegen := generator.Codegen
+		if *stubs {
+			codegen = generator.CodegenStubs
+		}
+		out, err := codegen(ctx, config)
+		if err != nil {
+			return err
+		}
+		return cmd.write(out)
+	}
+}
+
+// severities are the values of the -fail-on flag of diff.
+var severities = map[string]generator.Severity{
+	"safe":      generator.Safe,
+	"dangerous": generator.Dangerous,
+	"breaking":  generator.Breaking,
+}
+
+func diffCommand(cmd *command) func(files []string) error {
+	failOn := cmd.flags.String("fail-on", "breaking", "exit with 1 on changes of at least `severity` breaking, dangerous, safe or none")
+
+	return func(files []string) error {
+		if len(files) != 2 {
+			return usageError{errors.New("diff expects the old and the new schema")}
+		}
+		threshold, ok := severities[*failOn]
+		if !ok && *failOn != "none" {
+			return usageError{fmt.Errorf("unknown severity %q", *failOn)}
+		}
+		old, err := cmd.load(files[:1])
+		if err != nil {
+			return err
+		}
+		new, err := cmd.load(files[1:])
+		if err != nil {
+			return err
+		}
+
+		var out strings.Builder
+		failing := 0
+		for _, change := range generator.Diff(old, new) {
+			fmt.Fprintln(&out, change)
+			if ok && change.Severity >= threshold {
+				failing++
+			}
+		}
+		if err := cmd.write([]byte(out.String())); err != nil {
+			return err
+		}
+		if failing > 0 {
+			return fmt.Errorf("%d changes of severity %s or higher", failing, threshold)
+		}
+		return nil
+	}
+}
+
+func changelogCommand(cmd *command) func(files []string) error {
+	return func(files []string) error {
+		if len(files) < 2 {
+			return usageError{errors.New("changelog expects at least two releases")}
+		}
+		releases := make([]generator.Release, len(files))
+		for i, release := range files {
+			name, file, ok := strings.Cut(release, "=")
+			if !ok {
+				name, file = release, release
+			}
+			ctx, err := cmd.load([]string{file})
+			if err != nil {
+				return err
+			}
+			releases[i] = generator.Release{Name: name, Context: ctx}
+		}
+		return cmd.write([]byte(generator.Changelog(releases...)))
+	}
+}
+
+// docsFormats are the values of the -format flag of docs.
+var docsFormats = map[string]generator.DocsFormat{
+	"markdown": generator.DocsMarkdown,
+	"html":     generator.DocsHTML,
+}
+
+func docsCommand(cmd *command) func(files []string) error {
+	format := cmd.flags.String("format", "markdown", "`format` of the pages, markdown or html")
+	title := cmd.flags.String("title", "", "`title` of the index page")
+
+	return func(files []string) error {
+		if cmd.output == "" {
+			return usageError{errors.New("docs expects an output directory given by -o")}
+		}
+		docsFormat, ok := docsFormats[*format]
+		if !ok {
+			return usageError{fmt.Errorf("unknown format %q", *format)}
+		}
+		ctx, err := cmd.load(files)
+		if err != nil {
+			return err
+		}
+		pages, err := generator.Docs(ctx, generator.DocsConfig{Format: docsFormat, Title: *title})
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(cmd.output, 0755); err != nil {
+			return usageError{err}
+		}
+		for name, page := range pages {
+			if err := os.WriteFile(filepath.Join(cmd.output, name), page, 0644); err != nil {
+				return usageError{err}
+			}
+		}
+		return nil
+	}
+}
+
+// graphFormats are the values of the -format flag of graph.
+var graphFormats = map[string]generator.GraphFormat{
+	"dot":     generator.GraphDOT,
+	"mermaid": generator.GraphMermaid,
+}
+
+func graphCommand(cmd *command) func(files []string) error {
+	format := cmd.flags.String("format", "dot", "`format` of the diagram, dot or mermaid")
+	root := cmd.flags.String("root", "", "only draw the types reachable from the `type`")
+	depth := cmd.flags.Int("depth", 0, "only draw the types at most `n` edges away from -root, 0 for no limit")
+
+	return func(files []string) error {
+		graphFormat, ok := graphFormats[*format]
+		if !ok {
+			return usageError{fmt.Errorf("unknown format %q", *format)}
+		}
+		ctx, err := cmd.load(files)
+		if err != nil {
+			return err
+		}
+		graph, err := generator.Graph(ctx, generator.GraphConfig{Format: graphFormat, Root: *root, Depth: *depth})
+		if err != nil {
+			return usageError{err}
+		}
+		return cmd.write([]byte(graph))
+	}
+}
+
+func unusedCommand(cmd *command) func(files []string) error {
+	return func(files []string) error {
+		ctx, err := cmd.load(files)
+		if err != nil {
+			return err
+		}
+		unreachable := ctx.UnreachableTypes()
+		var out strings.Builder
+		for _, name := range unreachable {
+			fmt.Fprintln(&out, name)
+		}
+		if err := cmd.write([]byte(out.String())); err != nil {
+			return err
+		}
+		if len(unreachable) > 0 {
+			return fmt.Errorf("%d unreachable types", len(unreachable))
+		}
+		return nil
+	}
+}
+
+// lintLevels are the levels of the -rule flag of lint.
+var lintLevels = map[string]generator.LintLevel{
+	"off":     generator.LintOff,
+	"warn":    generator.LintWarn,
+	"warning": generator.LintWarn,
+	"error":   generator.LintError,
+}
+
+func lintCommand(cmd *command) func(files []string) error {
+	rules := mappingFlag{}
+	cmd.flags.Var(rules, "rule", "set the level of a rule to off, warn or error, e.g. `descriptions=error` (repeatable)")
+	list := cmd.flags.Bool("list", false, "list the rules with their default levels")
+
+	return func(files []string) error {
+		if *list {
+			var out strings.Builder
+			for _, rule := range generator.LintRules() {
+				fmt.Fprintf(&out, "%-17s %-8s %s\n", rule.Name, rule.Level, rule.Description)
+			}
+			return cmd.write([]byte(out.String()))
+		}
+		config := generator.LintConfig{}
+		for name, value := range rules {
+			level, ok := lintLevels[value]
+			if !ok {
+				return usageError{fmt.Errorf("unknown level %q of rule %s", value, name)}
+			}
+			config[name] = level
+		}
+		ctx, err := cmd.load(files)
+		if err != nil {
+			return err
+		}
+		diagnostics, err := generator.Lint(ctx, config)
+		if err != nil {
+			return usageError{err}
+		}
+
+		var out strings.Builder
+		failing := 0
+		for _, diagnostic := range diagnostics {
+			fmt.Fprintln(&out, diagnostic)
+			if diagnostic.Level == generator.LintError {
+				failing++
+			}
+		}
+		if err := cmd.write([]byte(out.String())); err != nil {
+			return err
+		}
+		if failing > 0 {
+			return fmt.Errorf("%d lint errors", failing)
+		}
+		return nil
+	}
+}
+
+func fmtCommand(cmd *command) func(files []string) error {
+	write := cmd.flags.Bool("w", false, "write the result to the files instead of stdout")
+	list := cmd.flags.Bool("l", false, "list the files whose formatting differs instead")
+	sortDefinitions := cmd.flags.Bool("sort", false, "sort definitions, fields and arguments by name")
+
+	return func(files []string) error {
+		if *write && len(files) == 0 {
+			return usageError{errors.New("fmt -w expects files")}
+		}
+		sources, err := cmd.read(files)
+		if err != nil {
+			return err
+		}
+		var options []generator.FormatOption
+		if *sortDefinitions {
+			options = append(options, generator.SortDefinitions())
+		}
+
+		var out strings.Builder
+		unformatted := 0
+		for _, src := range sources {
+			formatted, err := generator.Format(string(src.Body), options...)
+			if err != nil {
+				return err
+			}
+			switch {
+			case *list:
+				if formatted != string(src.Body) {
+					fmt.Fprintln(&out, src.Name)
+					unformatted++
+				}
+			case *write:
+				if formatted != string(src.Body) {
+					if err := os.WriteFile(src.Name, []byte(formatted), 0644); err != nil {
+						return usageError{err}
+					}
+				}
+			default:
+				out.WriteString(formatted)
+			}
+		}
+		if err := cmd.write([]byte(out.String())); err != nil {
+			return err
+		}
+		if unformatted > 0 {
+			return fmt.Errorf("%d files are not formatted", unformatted)
+		}
+		return nil
+	}
+}
+
+func lspCommand(cmd *command) func(files []string) error {
+	return func(files []string) error {
+		if len(files) > 0 {
+			return usageError{errors.New("lsp expects no files")}
+		}
+		return lsp.NewServer(cmd.stdin, cmd.stdout).Run()
+	}
+}
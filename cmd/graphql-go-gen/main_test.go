@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func runWithInput(input string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(input), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestValidateExitCodes(t *testing.T) {
+	code, _, stderr := runWithInput("type Query { hello: String }", "validate")
+	if code != exitOK {
+		t.Errorf("Expected exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+
+	code, _, stderr = runWithInput("type Query { hello: World }", "validate")
+	if code != exitSchema {
+		t.Errorf("Expected exit code %d, got %d", exitSchema, code)
+	}
+	if !strings.Contains(stderr, "1:1: Could not map type World") {
+		t.Errorf("Expected located error, got %q", stderr)
+	}
+}
+
+func TestUnknownCommand(t *testing.T) {
+	if code, _, _ := runWithInput("", "unknown"); code != exitUsage {
+		t.Errorf("Expected exit code %d, got %d", exitUsage, code)
+	}
+}
+
+func TestPrint(t *testing.T) {
+	code, stdout, stderr := runWithInput("type Query { b: Int a: String }", "print")
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+	expected := "type Query {\n  a: String\n  b: Int\n}\n"
+	if stdout != expected {
+		t.Errorf("Expected %q, got %q", expected, stdout)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "old.graphql")
+	os.WriteFile(old, []byte("type Query { a: String b: Int }"), 0644)
+	safe := filepath.Join(dir, "safe.graphql")
+	os.WriteFile(safe, []byte("type Query { a: String! b: Int c: Int }"), 0644)
+	breaking := filepath.Join(dir, "breaking.graphql")
+	os.WriteFile(breaking, []byte("type Query { a: String }"), 0644)
+
+	code, stdout, stderr := runWithInput("", "diff", old, safe)
+	if code != exitOK {
+		t.Errorf("Expected exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+	expected := "SAFE: Field Query.a changed type from String to String!.\nSAFE: Field Query.c was added.\n"
+	if stdout != expected {
+		t.Errorf("Expected %q, got %q", expected, stdout)
+	}
+
+	code, stdout, stderr = runWithInput("", "diff", old, breaking)
+	if code != exitSchema || stdout != "BREAKING: Field Query.b was removed.\n" {
+		t.Errorf("Expected exit code %d and the removed field, got %d: %q %q", exitSchema, code, stdout, stderr)
+	}
+	if code, _, _ := runWithInput("", "diff", "-fail-on", "none", old, breaking); code != exitOK {
+		t.Errorf("Expected exit code %d with -fail-on none, got %d", exitOK, code)
+	}
+	if code, _, _ := runWithInput("", "diff", "-fail-on", "safe", old, safe); code != exitSchema {
+		t.Errorf("Expected exit code %d with -fail-on safe, got %d", exitSchema, code)
+	}
+	if code, _, _ := runWithInput("", "diff", old); code != exitUsage {
+		t.Errorf("Expected exit code %d for a missing schema, got %d", exitUsage, code)
+	}
+}
+
+func TestChangelog(t *testing.T) {
+	dir := t.TempDir()
+	v1 := filepath.Join(dir, "v1.graphql")
+	os.WriteFile(v1, []byte("type Query { a: String }"), 0644)
+	v2 := filepath.Join(dir, "v2.graphql")
+	os.WriteFile(v2, []byte("type Query { a: String \"The b\" b: Int }"), 0644)
+
+	code, stdout, stderr := runWithInput("", "changelog", "v1="+v1, "v2="+v2)
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+	expected := "## v2\n\n### Added\n\n- Field `Query.b`: The b\n"
+	if stdout != expected {
+		t.Errorf("Expected %q, got %q", expected, stdout)
+	}
+	if code, _, _ := runWithInput("", "changelog", v1); code != exitUsage {
+		t.Errorf("Expected exit code %d for a single release, got %d", exitUsage, code)
+	}
+}
+
+func TestDocs(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "site")
+	code, _, stderr := runWithInput("type Query { me: User } type User { name: String }", "docs", "-format", "html", "-o", dir)
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+	for _, name := range []string{"index.html", "Query.html", "User.html"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Expected page %s: %s", name, err)
+		}
+	}
+	if code, _, _ := runWithInput("type Query { a: String }", "docs"); code != exitUsage {
+		t.Errorf("Expected exit code %d without an output directory, got %d", exitUsage, code)
+	}
+}
+
+func TestGraph(t *testing.T) {
+	code, stdout, stderr := runWithInput("type Query { me: User } type User { name: String }", "graph", "-format", "mermaid", "-root", "User")
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+	expected := "classDiagram\n\tclass User\n"
+	if stdout != expected {
+		t.Errorf("Expected %q, got %q", expected, stdout)
+	}
+	if code, _, _ := runWithInput("type Query { a: String }", "graph", "-root", "Missing"); code != exitUsage {
+		t.Errorf("Expected exit code %d for an unknown root, got %d", exitUsage, code)
+	}
+}
+
+func TestUnused(t *testing.T) {
+	input := "type Query { a: String } type Old { b: String }"
+	code, stdout, _ := runWithInput(input, "unused")
+	if code != exitSchema {
+		t.Errorf("Expected exit code %d, got %d", exitSchema, code)
+	}
+	if stdout != "Old\n" {
+		t.Errorf("Expected %q, got %q", "Old\n", stdout)
+	}
+
+	_, stdout, _ = runWithInput(input, "print", "-prune")
+	if strings.Contains(stdout, "Old") {
+		t.Errorf("Expected Old to be pruned, got:\n%s", stdout)
+	}
+}
+
+func TestLint(t *testing.T) {
+	input := "type Query { user_name: String }"
+	code, stdout, _ := runWithInput(input, "lint")
+	if code != exitSchema {
+		t.Errorf("Expected exit code %d, got %d", exitSchema, code)
+	}
+	expected := "1:14: error: Field Query.user_name is not camelCase. (field-names)\n"
+	if stdout != expected {
+		t.Errorf("Expected %q, got %q", expected, stdout)
+	}
+
+	if code, stdout, stderr := runWithInput(input, "lint", "-rule", "field-names=warn"); code != exitOK || stdout != strings.Replace(expected, "error", "warning", 1) {
+		t.Errorf("Expected a warning and exit code %d, got %d: %s%s", exitOK, code, stdout, stderr)
+	}
+	if code, _, _ := runWithInput(input, "lint", "-rule", "missing=warn"); code != exitUsage {
+		t.Errorf("Expected exit code %d for an unknown rule, got %d", exitUsage, code)
+	}
+}
+
+func TestFmt(t *testing.T) {
+	code, stdout, stderr := runWithInput("type Query{a:String # the a\n}", "fmt")
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+	expected := "type Query {\n  a: String # the a\n}\n"
+	if stdout != expected {
+		t.Errorf("Expected %q, got %q", expected, stdout)
+	}
+
+	file := filepath.Join(t.TempDir(), "schema.graphql")
+	os.WriteFile(file, []byte("type Query{a:String}"), 0644)
+	if code, stdout, _ := runWithInput("", "fmt", "-l", file); code != exitSchema || stdout != file+"\n" {
+		t.Errorf("Expected %s to be listed with exit code %d, got %d: %q", file, exitSchema, code, stdout)
+	}
+	if code, _, stderr := runWithInput("", "fmt", "-w", file); code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+	if written, _ := os.ReadFile(file); string(written) != "type Query {\n  a: String\n}\n" {
+		t.Errorf("Expected the file to be formatted, got %q", written)
+	}
+}
+
+func TestLSP(t *testing.T) {
+	var input string
+	for _, message := range []string{`{"jsonrpc":"2.0","id":1,"method":"shutdown"}`, `{"jsonrpc":"2.0","method":"exit"}`} {
+		input += fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(message), message)
+	}
+	code, stdout, stderr := runWithInput(input, "lsp")
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+	if !strings.Contains(stdout, `"id":1`) {
+		t.Errorf("Expected a response to shutdown, got %q", stdout)
+	}
+}
+
+// writeFileAtomically writes a file by renaming a temporary file, so that watchers
+// never see it partially written.
+func writeFileAtomically(t *testing.T, name, content string) {
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".tmp-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tmp.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := tmp.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	writeFileAtomically(t, "schema.graphql", "type Query { a: String }")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	runs := make(chan struct{})
+	oldContext, oldInterval, oldRan := watchContext, watchInterval, watchRan
+	t.Cleanup(func() { watchContext, watchInterval, watchRan = oldContext, oldInterval, oldRan })
+	watchContext = func() (context.Context, context.CancelFunc) { return ctx, cancel }
+	watchInterval = 10 * time.Millisecond
+	watchRan = func() {
+		select {
+		case runs <- struct{}{}:
+		case <-ctx.Done():
+		}
+	}
+
+	type result struct {
+		code           int
+		stdout, stderr string
+	}
+	done := make(chan result)
+	go func() {
+		code, stdout, stderr := runWithInput("", "validate", "-watch", "schema.graphql")
+		done <- result{code, stdout, stderr}
+	}()
+
+	waitForRun := func() {
+		select {
+		case <-runs:
+		case <-time.After(10 * time.Second):
+			t.Fatal("Expected the command to run")
+		}
+	}
+	waitForRun()
+	// The size tells the versions apart even if modification times are coarse
+	writeFileAtomically(t, "schema.graphql", "type Query { a: Missing }")
+	waitForRun()
+	cancel()
+
+	r := <-done
+	if r.code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d: %s", exitOK, r.code, r.stderr)
+	}
+	if !strings.Contains(r.stderr, "schema.graphql:1:1:") || !strings.Contains(r.stderr, "Missing") {
+		t.Errorf("Expected the located error of the changed schema, got %q", r.stderr)
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"github.com/graphql-go/graphql"
 	"testing"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 )
 
 func TestBasicServer(t *testing.T) {
@@ -42,10 +45,20 @@ func TestBasicServer(t *testing.T) {
 	 }
 	`
 
-	params := graphql.Params { Schema: schema, RequestString: query }
-	r := graphql.Do(params)
-	if len(r.Errors) > 0 {
-		fmt.Print("failed to execute graphql operation, errors: %+v", r.Errors)
+	server := httptest.NewServer(NewHandler(schema))
+	defer server.Close()
+
+	response, herr := http.Get(server.URL + "?query=" + url.QueryEscape(query))
+	if herr != nil {
+		fmt.Print(herr)
+		t.FailNow()
+	}
+	defer response.Body.Close()
+
+	var r graphql.Result
+	json.NewDecoder(response.Body).Decode(&r)
+	if response.StatusCode != http.StatusOK || len(r.Errors) > 0 {
+		fmt.Printf("failed to execute graphql operation, errors: %+v", r.Errors)
 		t.FailNow()
 	}
 	rJSON, _ := json.Marshal(r)
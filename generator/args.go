@@ -0,0 +1,84 @@
+package generator
+
+import (
+	"encoding/json"
+
+	"github.com/graphql-go/graphql"
+)
+
+// DecodeArgs decodes the arguments of a resolve call into target, usually a pointer
+// to a struct with json tags such as the argument structs emitted by Codegen. Enum
+// arguments are decoded to the names of their values.
+func DecodeArgs(p graphql.ResolveParams, target interface{}) error {
+	args := make(map[string]interface{}, len(p.Args))
+	for name, value := range p.Args {
+		args[name] = value
+	}
+
+	if field := resolvedField(p.Info); field != nil {
+		for _, arg := range field.Args {
+			if value, ok := args[arg.Name()]; ok {
+				args[arg.Name()] = enumNames(arg.Type, value)
+			}
+		}
+	}
+
+	encoded, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, target)
+}
+
+// resolvedField returns the definition of the field which is resolved.
+func resolvedField(info graphql.ResolveInfo) *graphql.FieldDefinition {
+	switch parent := info.ParentType.(type) {
+	case *graphql.Object:
+		return parent.Fields()[info.FieldName]
+	case *graphql.Interface:
+		return parent.Fields()[info.FieldName]
+	}
+	return nil
+}
+
+// enumNames replaces the enum values within value by their names.
+func enumNames(typ graphql.Type, value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	switch typ.(type) {
+	case *graphql.NonNull:
+		return enumNames(typ.(*graphql.NonNull).OfType, value)
+	case *graphql.List:
+		list, ok := value.([]interface{})
+		if !ok {
+			return value
+		}
+		names := make([]interface{}, len(list))
+		for i, item := range list {
+			names[i] = enumNames(typ.(*graphql.List).OfType, item)
+		}
+		return names
+	case *graphql.InputObject:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		fields := typ.(*graphql.InputObject).Fields()
+		names := make(map[string]interface{}, len(object))
+		for key, item := range object {
+			if field, ok := fields[key]; ok {
+				item = enumNames(field.Type, item)
+			}
+			names[key] = item
+		}
+		return names
+	case *graphql.Enum:
+		for _, enumValue := range typ.(*graphql.Enum).Values() {
+			if enumValue.Value == value {
+				return enumValue.Name
+			}
+		}
+	}
+	return value
+}
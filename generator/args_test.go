@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestDecodeArgs(t *testing.T) {
+	gql := `
+type Query {
+	users(role: Role, filter: UserFilter, first: Int = 10): String
+}
+enum Role { USER, ADMIN }
+input UserFilter {
+	name: String
+	roles: [Role!]
+}`
+
+	type userFilter struct {
+		Name  *string  `json:"name"`
+		Roles []string `json:"roles"`
+	}
+	type usersArgs struct {
+		Role   *string     `json:"role"`
+		Filter *userFilter `json:"filter"`
+		First  int         `json:"first"`
+	}
+
+	ctx, err := Generate(gql)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+
+	var decoded usersArgs
+	var decodeErr error
+	ctx.Object("Query").Fields()["users"].Resolve = func(p graphql.ResolveParams) (interface{}, error) {
+		decodeErr = DecodeArgs(p, &decoded)
+		return "ok", nil
+	}
+	schema, err := CreateSchemaFromContext(ctx)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+
+	r := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ users(role: ADMIN, filter: {name: "a", roles: [USER, ADMIN]}) }`,
+	})
+	if len(r.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", r.Errors)
+	}
+	if decodeErr != nil {
+		t.Fatalf("Unexpected decode error: %s", decodeErr)
+	}
+	if decoded.Role == nil || *decoded.Role != "ADMIN" || decoded.First != 10 {
+		t.Errorf("Unexpected arguments decoded: %+v", decoded)
+	}
+	if decoded.Filter == nil || *decoded.Filter.Name != "a" || len(decoded.Filter.Roles) != 2 || decoded.Filter.Roles[1] != "ADMIN" {
+		t.Errorf("Unexpected filter decoded: %+v", decoded.Filter)
+	}
+}
@@ -0,0 +1,151 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// RoleExtractor returns the roles of the requester from the context of a request.
+type RoleExtractor func(ctx context.Context) []string
+
+// AuthError is the error of fields the requester lacks the roles for. Its extension
+// code is UNAUTHENTICATED if the requester has no roles and FORBIDDEN otherwise.
+type AuthError struct {
+	Type     string
+	Field    string
+	Requires []string
+
+	authenticated bool
+}
+
+func (e *AuthError) Error() string {
+	return fmt.Sprintf("Not authorized to access %s.%s.", e.Type, e.Field)
+}
+
+// Extensions implements gqlerrors.ExtendedError.
+func (e *AuthError) Extensions() map[string]interface{} {
+	code := "UNAUTHENTICATED"
+	if e.authenticated {
+		code = "FORBIDDEN"
+	}
+	extensions := map[string]interface{}{"code": code}
+	if len(e.Requires) > 0 {
+		extensions["requires"] = e.Requires
+	}
+	return extensions
+}
+
+// Auth registers AuthDirective as @auth and @hasRole.
+func Auth(roles RoleExtractor) Option {
+	return func(gen *Generator) {
+		handler := AuthDirective(roles)
+		gen.RegisterDirective("auth", handler)
+		gen.RegisterDirective("hasRole", handler)
+	}
+}
+
+// AuthDirective returns a handler for @auth(requires: [Role!]) and @hasRole(role: Role)
+// on fields, objects and interfaces. Other locations fail the generation, as their
+// values cannot be protected. It wraps the resolvers of the annotated fields,
+// or of all fields of the annotated type, with a check that the requester has one of
+// the required roles. Without required roles any role grants access.
+//
+// A directive handled by AuthDirective on a field takes precedence over the one of
+// the type declaring the field, so that fields can relax or replace the roles of
+// their type.
+func AuthDirective(roles RoleExtractor) DirectiveHandler {
+	return func(site *DirectiveSite) error {
+		typeLevel := site.Location == graphql.DirectiveLocationObject || site.Location == graphql.DirectiveLocationInterface
+		if !typeLevel && site.Location != graphql.DirectiveLocationFieldDefinition {
+			return fmt.Errorf("Authorization is only supported on %s, %s and %s.",
+				graphql.DirectiveLocationFieldDefinition, graphql.DirectiveLocationObject, graphql.DirectiveLocationInterface)
+		}
+		site.ctx.authDirectives[site.Name] = true
+
+		var requires []string
+		for _, argName := range []string{"requires", "role"} {
+			switch arg := site.Args[argName].(type) {
+			case string:
+				requires = append(requires, arg)
+			case []interface{}:
+				for _, role := range arg {
+					requires = append(requires, fmt.Sprint(role))
+				}
+			}
+		}
+
+		owner := site.ctx
+		typeName := site.TypeName
+		return site.Wrap(func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+			// All directives are applied when the resolvers are wrapped
+			var overridden map[string]bool
+			if typeLevel {
+				overridden = owner.fieldsWithAuth(typeName)
+			}
+			return func(p graphql.ResolveParams) (interface{}, error) {
+				if overridden[p.Info.FieldName] {
+					return next(p)
+				}
+				ctx := p.Context
+				if ctx == nil {
+					ctx = context.Background()
+				}
+				granted := roles(ctx)
+				if !hasAnyRole(granted, requires) {
+					return nil, &AuthError{
+						Type:          p.Info.ParentType.Name(),
+						Field:         p.Info.FieldName,
+						Requires:      requires,
+						authenticated: len(granted) > 0,
+					}
+				}
+				return next(p)
+			}
+		})
+	}
+}
+
+func hasAnyRole(granted, requires []string) bool {
+	if len(requires) == 0 {
+		return len(granted) > 0
+	}
+	for _, role := range granted {
+		for _, required := range requires {
+			if role == required {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fieldsWithAuth returns the names of the fields of a type which have a directive
+// handled by AuthDirective, including those of extensions of the type.
+func (g *Context) fieldsWithAuth(typeName string) map[string]bool {
+	fields := make(map[string]bool)
+	for _, def := range g.document.Definitions {
+		if definitionName(def) != typeName {
+			continue
+		}
+		var fieldDefs []*ast.FieldDefinition
+		switch def := def.(type) {
+		case *ast.ObjectDefinition:
+			fieldDefs = def.Fields
+		case *ast.InterfaceDefinition:
+			fieldDefs = def.Fields
+		case *ast.TypeExtensionDefinition:
+			fieldDefs = def.Definition.Fields
+		}
+		for _, fieldDef := range fieldDefs {
+			for _, directive := range fieldDef.Directives {
+				if g.authDirectives[directive.Name.Value] {
+					fields[fieldDef.Name.Value] = true
+				}
+			}
+		}
+	}
+	return fields
+}
@@ -0,0 +1,109 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+type rolesKey struct{}
+
+func TestAuthDirective(t *testing.T) {
+	gql := `
+directive @auth(requires: [Role!]) on FIELD_DEFINITION | OBJECT
+directive @hasRole(role: Role!) on FIELD_DEFINITION
+enum Role { ADMIN USER }
+
+type Query {
+	public: String
+	me: String @auth
+	users: String @auth(requires: [ADMIN])
+	stats: Stats
+}
+type Stats @auth(requires: USER) {
+	count: Int
+	secret: String @hasRole(role: ADMIN)
+	hint: String @auth
+}`
+
+	ctx, err := New(Auth(func(ctx context.Context) []string {
+		roles, _ := ctx.Value(rolesKey{}).([]string)
+		return roles
+	})).Generate(gql)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	if errs := ctx.Errors(); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+	for _, field := range []string{"public", "me", "users"} {
+		ctx.SetResolver("Query", field, func(p graphql.ResolveParams) (interface{}, error) {
+			return "ok", nil
+		})
+	}
+	ctx.SetResolver("Query", "stats", func(p graphql.ResolveParams) (interface{}, error) {
+		return map[string]interface{}{"count": 1, "secret": "s", "hint": "h"}, nil
+	})
+	schema, err := CreateSchemaFromContext(ctx)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+
+	tests := []struct {
+		roles    []string
+		query    string
+		expected string
+	}{
+		{nil, `{ public }`, `{"data":{"public":"ok"}}`},
+		{nil, `{ me }`, `{"data":{"me":null},"errors":[{"message":"Not authorized to access Query.me.","locations":[{"line":1,"column":3}],"path":["me"],"extensions":{"code":"UNAUTHENTICATED"}}]}`},
+		{[]string{"USER"}, `{ me }`, `{"data":{"me":"ok"}}`},
+		{[]string{"USER"}, `{ users }`, `{"data":{"users":null},"errors":[{"message":"Not authorized to access Query.users.","locations":[{"line":1,"column":3}],"path":["users"],"extensions":{"code":"FORBIDDEN","requires":["ADMIN"]}}]}`},
+		{[]string{"ADMIN"}, `{ users }`, `{"data":{"users":"ok"}}`},
+		{[]string{"USER"}, `{ stats { count } }`, `{"data":{"stats":{"count":1}}}`},
+		{[]string{"ADMIN"}, `{ stats { count } }`, `{"data":{"stats":{"count":null}},"errors":[{"message":"Not authorized to access Stats.count.","locations":[{"line":1,"column":11}],"path":["stats","count"],"extensions":{"code":"FORBIDDEN","requires":["USER"]}}]}`},
+		{[]string{"ADMIN"}, `{ stats { secret } }`, `{"data":{"stats":{"secret":"s"}}}`},
+		{[]string{"GUEST"}, `{ stats { hint } }`, `{"data":{"stats":{"hint":"h"}}}`},
+		{nil, `{ stats { hint } }`, `{"data":{"stats":{"hint":null}},"errors":[{"message":"Not authorized to access Stats.hint.","locations":[{"line":1,"column":11}],"path":["stats","hint"],"extensions":{"code":"UNAUTHENTICATED"}}]}`},
+		{[]string{"USER"}, `{ stats { secret } }`, `{"data":{"stats":{"secret":null}},"errors":[{"message":"Not authorized to access Stats.secret.","locations":[{"line":1,"column":11}],"path":["stats","secret"],"extensions":{"code":"FORBIDDEN","requires":["ADMIN"]}}]}`},
+	}
+	for _, test := range tests {
+		r := graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: test.query,
+			Context:       context.WithValue(context.Background(), rolesKey{}, test.roles),
+		})
+		result, _ := json.Marshal(r)
+		if string(result) != test.expected {
+			t.Errorf("Unexpected result of %s with roles %v:\n%s", test.query, test.roles, result)
+		}
+	}
+}
+
+func TestAuthDirectiveUnsupportedLocations(t *testing.T) {
+	tests := []struct {
+		gql      string
+		expected string
+	}{
+		{`input I { secret: String @auth(requires: ["ADMIN"]) }`, "I.secret"},
+		{`enum E { B @hasRole(role: "ADMIN") }`, "E.B"},
+		{`scalar S @auth(requires: ["ADMIN"])`, "S"},
+		{`type Query { users(filter: String @auth): String }`, "Query.users(filter:)"},
+	}
+	for _, test := range tests {
+		ctx, err := New(Auth(func(ctx context.Context) []string { return nil })).Generate(test.gql)
+		if err != nil {
+			fmt.Print(err)
+			t.FailNow()
+		}
+		errs := ctx.Errors()
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), " on "+test.expected+": Authorization is only supported on FIELD_DEFINITION, OBJECT and INTERFACE.") {
+			t.Errorf("Expected an unsupported location error for %s, got %v", test.expected, errs)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Release is a version of a schema.
+type Release struct {
+	Name    string
+	Context *Context
+}
+
+// changelogSections are the sections of a release in the changelog.
+var changelogSections = []struct {
+	kind  ChangeKind
+	title string
+}{
+	{Added, "Added"},
+	{Deprecated, "Deprecated"},
+	{Removed, "Removed"},
+	{Changed, "Changed"},
+}
+
+// Changelog returns a Markdown changelog of releases, given from the oldest to the
+// newest. Every release but the first gets a section listing the types, fields,
+// arguments, enum values and directives added, deprecated and removed since the
+// previous release together with their descriptions and deprecation reasons, and
+// the other changes found by Diff. Breaking changes are marked. The newest release
+// comes first.
+func Changelog(releases ...Release) string {
+	var sections []string
+	for i := len(releases) - 1; i > 0; i-- {
+		old, new := releases[i-1].Context, releases[i].Context
+		changes := Diff(old, new)
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "## %s\n", releases[i].Name)
+		if len(changes) == 0 {
+			b.WriteString("\nNo changes.\n")
+		}
+		for _, section := range changelogSections {
+			var items []string
+			for _, change := range changes {
+				if change.Kind == section.kind {
+					items = append(items, changelogItem(old, new, change))
+				}
+			}
+			if len(items) > 0 {
+				fmt.Fprintf(&b, "\n### %s\n\n%s\n", section.title, strings.Join(items, "\n"))
+			}
+		}
+		sections = append(sections, b.String())
+	}
+	return strings.Join(sections, "\n")
+}
+
+func changelogItem(old, new *Context, change Change) string {
+	var item string
+	switch change.Kind {
+	case Added:
+		item = fmt.Sprintf("%s `%s`%s", elementName(new, change.Path), change.Path, changelogText(describe(new, change.Path)))
+	case Removed:
+		item = fmt.Sprintf("%s `%s`%s", elementName(old, change.Path), change.Path, changelogText(describe(old, change.Path)))
+	case Deprecated:
+		_, reason := describeField(new, change.Path)
+		item = fmt.Sprintf("%s `%s`%s", elementName(new, change.Path), change.Path, changelogText(reason))
+	default:
+		item = change.Message
+	}
+	if change.Severity == Breaking {
+		item += " **Breaking.**"
+	}
+	return "- " + item
+}
+
+func changelogText(text string) string {
+	if text == "" {
+		return ""
+	}
+	return ": " + strings.Join(strings.Fields(text), " ")
+}
+
+// elementName names the kind of element at a path of Change.
+func elementName(ctx *Context, path string) string {
+	switch {
+	case strings.Contains(path, "("):
+		return "Argument"
+	case strings.HasPrefix(path, "@"):
+		return "Directive"
+	case strings.Contains(path, "."):
+		typeName, _, _ := strings.Cut(path, ".")
+		switch typ, _ := ctx.GetObject(typeName); typ.(type) {
+		case *graphql.Enum:
+			return "Enum value"
+		case *graphql.InputObject:
+			return "Input field"
+		}
+		return "Field"
+	}
+	return "Type"
+}
+
+// describe returns the description of the element at a path of Change.
+func describe(ctx *Context, path string) string {
+	if fieldPath, argName, isArg := strings.Cut(path, "("); isArg {
+		argName = strings.TrimSuffix(argName, ":)")
+		var args graphql.FieldConfigArgument
+		if strings.HasPrefix(fieldPath, "@") {
+			args = ctx.directiveConfigs[strings.TrimPrefix(fieldPath, "@")].Args
+		} else if field := lookupField(ctx, fieldPath); field != nil {
+			args = field.Args
+		}
+		if arg, ok := args[argName]; ok {
+			return arg.Description
+		}
+		return ""
+	}
+	if strings.HasPrefix(path, "@") {
+		return ctx.directiveConfigs[strings.TrimPrefix(path, "@")].Description
+	}
+	if strings.Contains(path, ".") {
+		description, _ := describeField(ctx, path)
+		return description
+	}
+	if typ, ok := ctx.GetObject(path); ok {
+		return typ.Description()
+	}
+	return ""
+}
+
+// describeField returns the description and the deprecation reason of a field, input
+// field or enum value.
+func describeField(ctx *Context, path string) (description, deprecationReason string) {
+	typeName, fieldName, _ := strings.Cut(path, ".")
+	if field := lookupField(ctx, path); field != nil {
+		return field.Description, field.DeprecationReason
+	}
+	if fields, ok := ctx.inputConfigs[typeName].Fields.(graphql.InputObjectConfigFieldMap); ok {
+		if field, ok := fields[fieldName]; ok {
+			return field.Description, ""
+		}
+	}
+	for _, value := range ctx.enumValues(typeName) {
+		if value.Name == fieldName {
+			return value.Description, value.DeprecationReason
+		}
+	}
+	return "", ""
+}
+
+// lookupField returns the field of an object or interface at a path like Type.field.
+func lookupField(ctx *Context, path string) *graphql.Field {
+	typeName, fieldName, _ := strings.Cut(path, ".")
+	if config, ok := ctx.objectConfigs[typeName]; ok {
+		return configFields(config.Fields)[fieldName]
+	}
+	if config, ok := ctx.interfaceConfigs[typeName]; ok {
+		return configFields(config.Fields)[fieldName]
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package generator
+
+import (
+	"testing"
+)
+
+func TestChangelog(t *testing.T) {
+	ctxs := generateAll(t, `
+type Query {
+	user: User
+	users: [User]
+	legacy: String
+}
+"A user"
+type User {
+	name: String
+	"The nickname"
+	nick: String
+}`, `
+type Query {
+	user: User
+	users: [User]
+	legacy: String @deprecated(reason: "Use user.")
+	"""
+	The signed in
+	user
+	"""
+	me: User
+}
+"A user"
+type User {
+	name: String
+	role: Role
+}
+enum Role {
+	ADMIN
+}`, `
+type Query {
+	user: User
+	users: [User]
+	legacy: String @deprecated(reason: "Use user.")
+	me: User
+}
+"A user"
+type User {
+	name: String
+	role: Role
+}
+enum Role {
+	ADMIN
+}`)
+
+	changelog := Changelog(Release{"v1", ctxs[0]}, Release{"v2", ctxs[1]}, Release{"v3", ctxs[2]})
+	expected := "## v3\n" +
+		"\n" +
+		"No changes.\n" +
+		"\n" +
+		"## v2\n" +
+		"\n" +
+		"### Added\n" +
+		"\n" +
+		"- Field `Query.me`: The signed in user\n" +
+		"- Type `Role`\n" +
+		"- Field `User.role`\n" +
+		"\n" +
+		"### Deprecated\n" +
+		"\n" +
+		"- Field `Query.legacy`: Use user.\n" +
+		"\n" +
+		"### Removed\n" +
+		"\n" +
+		"- Field `User.nick`: The nickname **Breaking.**\n"
+	if changelog != expected {
+		t.Errorf("Expected changelog:\n%s\ngot:\n%s", expected, changelog)
+	}
+}
@@ -0,0 +1,132 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ImportPath is the import path of this package as used by generated code.
+const ImportPath = "github.com/alpox/graphql-go-gen/generator"
+
+// CodegenConfig configures the Go code emitted by Codegen.
+type CodegenConfig struct {
+	// Package is the package name of the generated file.
+	Package string
+
+	// Models emits a struct per object and input object, a string type with
+	// constants per enum, an interface per interface and union and a struct with a
+	// typed decoder for the arguments of each field taking arguments.
+	Models bool
+
+	// Resolvers emits a resolver interface per object with a method per field, a
+	// Resolvers struct holding implementations of them and a Bind function wiring
+	// them into a Context. It implies Models.
+	Resolvers bool
+
+	// Scalars maps custom scalars to the Go type of their values in the models,
+	// e.g. "Time" to "time.Time". Unmapped custom scalars are interface{}.
+	Scalars map[string]string
+}
+
+// goFile collects the imports and declarations of a generated Go file.
+type goFile struct {
+	// edited files are meant to be edited and lack the generated code header.
+	edited  bool
+	pkg     string
+	imports map[string]bool
+	body    bytes.Buffer
+}
+
+func newGoFile(pkg string) *goFile {
+	return &goFile{pkg: pkg, imports: make(map[string]bool)}
+}
+
+func (f *goFile) use(importPath string) {
+	f.imports[importPath] = true
+}
+
+func (f *goFile) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&f.body, format, args...)
+}
+
+func (f *goFile) bytes() ([]byte, error) {
+	var out bytes.Buffer
+	if !f.edited {
+		out.WriteString("// Code generated by graphql-go-gen. DO NOT EDIT.\n\n")
+	}
+	fmt.Fprintf(&out, "package %s\n\n", f.pkg)
+
+	if len(f.imports) > 0 {
+		var paths []string
+		for path := range f.imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		out.WriteString("import (\n")
+		for _, path := range paths {
+			fmt.Fprintf(&out, "\t%q\n", path)
+		}
+		out.WriteString(")\n\n")
+	}
+
+	out.Write(f.body.Bytes())
+
+	formatted, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("Generated code could not be formatted: %s", err)
+	}
+	return formatted, nil
+}
+
+// Codegen emits a formatted Go file which embeds the normalized SDL of the context
+// together with a constructor generating a Context from it. The config selects the
+// code generated in addition.
+func Codegen(ctx *Context, config CodegenConfig) ([]byte, error) {
+	if config.Package == "" {
+		return nil, fmt.Errorf("Codegen: No package name given.")
+	}
+
+	file := newGoFile(config.Package)
+	file.use(ImportPath)
+
+	file.printf("// Schema is the normalized SDL this file was generated from.\n")
+	file.printf("const Schema = %s\n\n", goString(PrintSchema(ctx)))
+
+	if config.Resolvers {
+		config.Models = true
+	}
+
+	if err := checkModelNames(ctx, config); err != nil {
+		return nil, err
+	}
+	newContext := "generator.Generate(Schema)"
+	if config.Models {
+		newContext = "generator.New(generator.EnumValues(EnumValues)).Generate(Schema)"
+	}
+	file.printf("// NewContext generates a Context from Schema.\n")
+	file.printf("func NewContext() (*generator.Context, error) {\n")
+	file.printf("\treturn %s\n", newContext)
+	file.printf("}\n\n")
+
+	if config.Models {
+		generateEnumValueMap(ctx, file)
+		generateModels(ctx, config, file)
+	}
+	if config.Resolvers {
+		generateResolvers(ctx, config, file)
+	}
+
+	return file.bytes()
+}
+
+// goString returns a Go string literal for str, preferring a raw string literal.
+func goString(str string) string {
+	if strings.Contains(str, "`") || strings.Contains(str, "\r") {
+		return strconv.Quote(str)
+	}
+	return "`\n" + str + "`"
+}
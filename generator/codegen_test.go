@@ -0,0 +1,190 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+)
+
+func TestCodegenEmbedsSchema(t *testing.T) {
+	gql := `
+type Query {
+	hello: String
+}`
+
+	ctx, err := Generate(gql)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	code, err := Codegen(ctx, CodegenConfig{Package: "api"})
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+
+	for _, expected := range []string{
+		"package api",
+		"const Schema = `\ntype Query {\n  hello: String\n}\n`",
+		"func NewContext() (*generator.Context, error) {",
+	} {
+		if !strings.Contains(string(code), expected) {
+			t.Errorf("Expected generated code to contain %q:\n%s", expected, code)
+		}
+	}
+}
+
+func TestCodegenModels(t *testing.T) {
+	gql := `
+type Query {
+	users(role: Role, filter: UserFilter): [User!]!
+}
+interface Node {
+	id: ID!
+}
+type User implements Node {
+	id: ID!
+	name: String
+	role: Role!
+	friends: [User]
+}
+enum Role { USER, SUPER_ADMIN }
+input UserFilter {
+	nameContains: String
+	roles: [Role!]
+}`
+
+	ctx, err := Generate(gql)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	code, err := Codegen(ctx, CodegenConfig{Package: "api", Models: true})
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+
+	for _, expected := range []string{
+		"return generator.New(generator.EnumValues(EnumValues)).Generate(Schema)",
+		"\"Role\": {\n\t\t\"USER\":        RoleUser,\n\t\t\"SUPER_ADMIN\": RoleSuperAdmin,\n\t},",
+		"type Role string",
+		"RoleSuperAdmin Role = \"SUPER_ADMIN\"",
+		"type Node interface {\n\tIsNode()\n}",
+		"type User struct {\n\tFriends []*User `json:\"friends\"`\n\tID      string  `json:\"id\"`\n\tName    *string `json:\"name\"`\n\tRole    Role    `json:\"role\"`\n}",
+		"func (User) IsNode() {}",
+		"type UserFilter struct {\n\tNameContains *string `json:\"nameContains\"`\n\tRoles        []Role  `json:\"roles\"`\n}",
+		"type QueryUsersArgs struct {\n\tFilter *UserFilter `json:\"filter\"`\n\tRole   *Role       `json:\"role\"`\n}",
+		"func DecodeQueryUsersArgs(p graphql.ResolveParams) (QueryUsersArgs, error) {",
+	} {
+		if !strings.Contains(string(code), expected) {
+			t.Errorf("Expected generated code to contain %q:\n%s", expected, code)
+		}
+	}
+	if strings.Contains(string(code), "type Query struct") {
+		t.Errorf("Expected no model for the Query root type")
+	}
+}
+
+func TestGoName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"user":        "User",
+		"userId":      "UserID",
+		"SUPER_ADMIN": "SuperAdmin",
+		"htmlURL":     "HTMLURL",
+		"first_name":  "FirstName",
+	} {
+		if got := goName(name); got != expected {
+			t.Errorf("Expected goName(%q) to be %q, got %q", name, expected, got)
+		}
+	}
+}
+
+// typeCheck parses and type checks generated files as one package.
+func typeCheck(t *testing.T, sources ...[]byte) {
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for i, src := range sources {
+		file, err := parser.ParseFile(fset, fmt.Sprintf("generated%d.go", i), src, 0)
+		if err != nil {
+			t.Fatalf("Generated code does not parse: %s\n%s", err, src)
+		}
+		files = append(files, file)
+	}
+	config := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := config.Check("api", fset, files, nil); err != nil {
+		t.Fatalf("Generated code does not compile: %s\n%s", err, sources[0])
+	}
+}
+
+func TestCodegenCompiles(t *testing.T) {
+	gql := `
+type Query {
+	users(role: Role, filter: UserFilter): [User!]!
+	search(text: String!): [Result!]!
+}
+type Mutation {
+	rename(id: ID!, name: String!): User
+}
+interface Node {
+	id: ID!
+}
+type User implements Node {
+	id: ID!
+	name: String
+	role: Role!
+	friends(first: Int): [User]
+}
+type Group {
+	name: String!
+}
+union Result = User | Group
+enum Role { USER, SUPER_ADMIN }
+input UserFilter {
+	nameContains: String
+	roles: [Role!]
+}`
+
+	ctx, err := Generate(gql)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	code, err := Codegen(ctx, CodegenConfig{Package: "api", Resolvers: true})
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	stubs, err := CodegenStubs(ctx, CodegenConfig{Package: "api"})
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	typeCheck(t, code, stubs)
+}
+
+func TestCodegenNameCollisions(t *testing.T) {
+	for gql, expected := range map[string]string{
+		"type Query { a: Schema } type Schema { a: Int }":                                                 "Codegen: the constant Schema and type Schema both map to the Go identifier Schema.",
+		"type Query { a: Bind } type Bind { a: Int }":                                                     "Codegen: type Bind and the function Bind both map to the Go identifier Bind.",
+		"type Query { a(x: Int): Int } type QueryAArgs { a: Int }":                                        "Codegen: the arguments of Query.a and type QueryAArgs both map to the Go identifier QueryAArgs.",
+		"type Query { a: Role } enum Role { A } type RoleA { a: Int }":                                    "Codegen: enum value Role.A and type RoleA both map to the Go identifier RoleA.",
+		"type Query { a: User } type User { a: Int } type UserResolver { a: Int }":                        "Codegen: type UserResolver and the resolver interface of User both map to the Go identifier UserResolver.",
+		"type Query { a: User } interface Node { isNode: Int } type User implements Node { isNode: Int }": "Codegen: field User.isNode and the marker method of Node both map to the Go identifier IsNode.",
+		"type Query { a: User } type User { user_id: Int, userId: Int }":                                  "Codegen: field User.userId and field User.user_id both map to the Go identifier UserID.",
+	} {
+		ctx, err := Generate(gql)
+		if err != nil {
+			fmt.Print(err)
+			t.FailNow()
+		}
+		if _, err := Codegen(ctx, CodegenConfig{Package: "api", Resolvers: true}); err == nil || err.Error() != expected {
+			t.Errorf("Expected %q for %s, got %v", expected, gql, err)
+		}
+	}
+}
@@ -0,0 +1,360 @@
+package generator
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// ConnectionDirective turns list fields into Relay connections, e.g. `friends: [User!]!
+// @connection` into `friends(first: Int, after: String, last: Int, before: String):
+// UserConnection!`. The UserConnection, UserEdge and PageInfo types are generated
+// unless the SDL defines them.
+const ConnectionDirective = "connection"
+
+// connectionArgs are the arguments of connection fields.
+var connectionArgs = []struct{ name, typ string }{
+	{"first", "Int"},
+	{"after", "String"},
+	{"last", "Int"},
+	{"before", "String"},
+}
+
+// expandConnections rewrites the fields marked with @connection in astDoc and appends
+// the definitions of their connection types. The directive of rewritten fields is
+// removed, so that only invalid uses remain for connectionDirective to report. With
+// shareable the generated types are marked @shareable, as every subgraph using
+// connections defines them.
+func expandConnections(astDoc *ast.Document, shareable bool) {
+	defined := make(map[string]bool)
+	for _, def := range astDoc.Definitions {
+		if _, ok := def.(*ast.TypeExtensionDefinition); !ok {
+			defined[definitionName(def)] = true
+		}
+	}
+
+	var added []ast.Node
+	define := func(name string, fields ...*ast.FieldDefinition) {
+		if defined[name] {
+			return
+		}
+		defined[name] = true
+		var directives []*ast.Directive
+		if shareable {
+			directives = append(directives, ast.NewDirective(&ast.Directive{
+				Name: ast.NewName(&ast.Name{Value: "shareable"}),
+			}))
+		}
+		added = append(added, ast.NewObjectDefinition(&ast.ObjectDefinition{
+			Name:       ast.NewName(&ast.Name{Value: name}),
+			Directives: directives,
+			Fields:     fields,
+		}))
+	}
+
+	for _, def := range astDoc.Definitions {
+		var fieldDefs []*ast.FieldDefinition
+		switch def.(type) {
+		case *ast.ObjectDefinition:
+			fieldDefs = def.(*ast.ObjectDefinition).Fields
+		case *ast.InterfaceDefinition:
+			fieldDefs = def.(*ast.InterfaceDefinition).Fields
+		case *ast.TypeExtensionDefinition:
+			fieldDefs = def.(*ast.TypeExtensionDefinition).Definition.Fields
+		}
+
+		for _, fieldDef := range fieldDefs {
+			if !hasDirective(fieldDef.Directives, ConnectionDirective) {
+				continue
+			}
+			listType, nonNull := fieldDef.Type, false
+			if t, ok := listType.(*ast.NonNull); ok {
+				listType, nonNull = t.Type, true
+			}
+			list, ok := listType.(*ast.List)
+			if !ok {
+				continue
+			}
+			nodeName := typeString(list.Type)
+			nodeName = strings.TrimSuffix(nodeName, "!")
+			if strings.HasPrefix(nodeName, "[") {
+				continue // Lists of lists have no node type
+			}
+
+			connectionName := nodeName + "Connection"
+			edgeName := nodeName + "Edge"
+			define("PageInfo",
+				sdlField("hasNextPage", astNonNull(astNamed("Boolean"))),
+				sdlField("hasPreviousPage", astNonNull(astNamed("Boolean"))),
+				sdlField("startCursor", astNamed("String")),
+				sdlField("endCursor", astNamed("String")),
+			)
+			define(edgeName,
+				sdlField("node", list.Type),
+				sdlField("cursor", astNonNull(astNamed("String"))),
+			)
+			define(connectionName,
+				sdlField("edges", astNonNull(ast.NewList(&ast.List{Type: astNonNull(astNamed(edgeName))}))),
+				sdlField("pageInfo", astNonNull(astNamed("PageInfo"))),
+			)
+
+			var fieldType ast.Type = astNamed(connectionName)
+			if nonNull {
+				fieldType = astNonNull(fieldType)
+			}
+			fieldDef.Type = fieldType
+			for _, arg := range connectionArgs {
+				if !hasArgument(fieldDef, arg.name) {
+					fieldDef.Arguments = append(fieldDef.Arguments, ast.NewInputValueDefinition(&ast.InputValueDefinition{
+						Name: ast.NewName(&ast.Name{Value: arg.name}),
+						Type: astNamed(arg.typ),
+					}))
+				}
+			}
+			fieldDef.Directives = withoutDirective(fieldDef.Directives, ConnectionDirective)
+		}
+	}
+	astDoc.Definitions = append(astDoc.Definitions, added...)
+}
+
+func sdlField(name string, typ ast.Type) *ast.FieldDefinition {
+	return ast.NewFieldDefinition(&ast.FieldDefinition{
+		Name: ast.NewName(&ast.Name{Value: name}),
+		Type: typ,
+	})
+}
+
+func astNamed(name string) *ast.Named {
+	return ast.NewNamed(&ast.Named{Name: ast.NewName(&ast.Name{Value: name})})
+}
+
+func astNonNull(typ ast.Type) *ast.NonNull {
+	return ast.NewNonNull(&ast.NonNull{Type: typ})
+}
+
+func hasArgument(fieldDef *ast.FieldDefinition, name string) bool {
+	for _, arg := range fieldDef.Arguments {
+		if arg.Name.Value == name {
+			return true
+		}
+	}
+	return false
+}
+
+func withoutDirective(directives []*ast.Directive, name string) []*ast.Directive {
+	var kept []*ast.Directive
+	for _, directive := range directives {
+		if directive.Name.Value != name {
+			kept = append(kept, directive)
+		}
+	}
+	return kept
+}
+
+// connectionDirective reports the uses of @connection expandConnections left alone.
+func connectionDirective(site *DirectiveSite) error {
+	return fmt.Errorf("Only fields of list types can be connections.")
+}
+
+// ConnectionArgs are the arguments of a connection field.
+type ConnectionArgs struct {
+	First  *int    `json:"first"`
+	After  *string `json:"after"`
+	Last   *int    `json:"last"`
+	Before *string `json:"before"`
+}
+
+// NewConnectionArgs reads the connection arguments from the arguments of a resolve call.
+func NewConnectionArgs(args map[string]interface{}) ConnectionArgs {
+	var connArgs ConnectionArgs
+	if first, ok := args["first"].(int); ok {
+		connArgs.First = &first
+	}
+	if after, ok := args["after"].(string); ok {
+		connArgs.After = &after
+	}
+	if last, ok := args["last"].(int); ok {
+		connArgs.Last = &last
+	}
+	if before, ok := args["before"].(string); ok {
+		connArgs.Before = &before
+	}
+	return connArgs
+}
+
+// Connection is a value of a connection type.
+type Connection struct {
+	Edges    []Edge   `json:"edges"`
+	PageInfo PageInfo `json:"pageInfo"`
+}
+
+// Edge is a value of an edge type.
+type Edge struct {
+	Node   interface{} `json:"node"`
+	Cursor string      `json:"cursor"`
+}
+
+// PageInfo is a value of the PageInfo type.
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor"`
+	EndCursor       *string `json:"endCursor"`
+}
+
+func newConnection(nodes []interface{}, cursors []string, hasPrevious, hasNext bool) *Connection {
+	conn := &Connection{Edges: make([]Edge, len(nodes))}
+	for i, node := range nodes {
+		conn.Edges[i] = Edge{Node: node, Cursor: cursors[i]}
+	}
+	conn.PageInfo.HasPreviousPage = hasPrevious
+	conn.PageInfo.HasNextPage = hasNext
+	if len(cursors) > 0 {
+		conn.PageInfo.StartCursor = &cursors[0]
+		conn.PageInfo.EndCursor = &cursors[len(cursors)-1]
+	}
+	return conn
+}
+
+const offsetCursorPrefix = "offset:"
+
+// OffsetCursor returns the cursor of the item at offset in a slice.
+func OffsetCursor(offset int) string {
+	return base64.StdEncoding.EncodeToString([]byte(offsetCursorPrefix + strconv.Itoa(offset)))
+}
+
+func cursorOffset(cursor string) (int, error) {
+	decoded, err := base64.StdEncoding.DecodeString(cursor)
+	if err == nil && strings.HasPrefix(string(decoded), offsetCursorPrefix) {
+		if offset, err := strconv.Atoi(strings.TrimPrefix(string(decoded), offsetCursorPrefix)); err == nil {
+			return offset, nil
+		}
+	}
+	return 0, fmt.Errorf("Invalid cursor %q.", cursor)
+}
+
+func checkCounts(args ConnectionArgs) error {
+	if args.First != nil && *args.First < 0 {
+		return fmt.Errorf("first must not be negative.")
+	}
+	if args.Last != nil && *args.Last < 0 {
+		return fmt.Errorf("last must not be negative.")
+	}
+	return nil
+}
+
+// ConnectionFromSlice returns the page of slice selected by args. The cursors are
+// the offsets of the items in slice.
+func ConnectionFromSlice(slice interface{}, args ConnectionArgs) (*Connection, error) {
+	value := reflect.ValueOf(slice)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return nil, fmt.Errorf("ConnectionFromSlice: Expected a slice, got %T.", slice)
+	}
+	if err := checkCounts(args); err != nil {
+		return nil, err
+	}
+
+	start, end := 0, value.Len()
+	if args.After != nil {
+		after, err := cursorOffset(*args.After)
+		if err != nil {
+			return nil, err
+		}
+		if after+1 > start {
+			start = after + 1
+		}
+	}
+	if args.Before != nil {
+		before, err := cursorOffset(*args.Before)
+		if err != nil {
+			return nil, err
+		}
+		if before < end {
+			end = before
+		}
+	}
+	if start > end {
+		start = end
+	}
+	if args.First != nil && start+*args.First < end {
+		end = start + *args.First
+	}
+	if args.Last != nil && end-*args.Last > start {
+		start = end - *args.Last
+	}
+
+	nodes := make([]interface{}, 0, end-start)
+	cursors := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		nodes = append(nodes, value.Index(i).Interface())
+		cursors = append(cursors, OffsetCursor(i))
+	}
+	return newConnection(nodes, cursors, start > 0, end < value.Len()), nil
+}
+
+// CursorSource is a source of nodes ordered by cursors, e.g. a table ordered by a
+// unique key encoded as cursor.
+type CursorSource interface {
+	// After returns up to limit nodes following the cursor, or the first nodes if
+	// cursor is nil, together with their cursors.
+	After(ctx context.Context, cursor *string, limit int) (nodes []interface{}, cursors []string, err error)
+
+	// Before returns up to limit nodes preceding the cursor, or the last nodes if
+	// cursor is nil, in order together with their cursors.
+	Before(ctx context.Context, cursor *string, limit int) (nodes []interface{}, cursors []string, err error)
+}
+
+// checkCursors reports a page of a CursorSource which has not exactly one cursor per
+// node.
+func checkCursors(nodes []interface{}, cursors []string) error {
+	if len(nodes) != len(cursors) {
+		return fmt.Errorf("ConnectionFromSource: The source returned %d nodes but %d cursors.", len(nodes), len(cursors))
+	}
+	return nil
+}
+
+// ConnectionFromSource returns the page of source selected by args. Either first or
+// last is required. One more node than requested is fetched to tell whether there
+// are further pages in the direction of paging; in the other direction a page is
+// reported if the page starts at a cursor.
+func ConnectionFromSource(ctx context.Context, source CursorSource, args ConnectionArgs) (*Connection, error) {
+	if err := checkCounts(args); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case args.First != nil:
+		nodes, cursors, err := source.After(ctx, args.After, *args.First+1)
+		if err == nil {
+			err = checkCursors(nodes, cursors)
+		}
+		if err != nil {
+			return nil, err
+		}
+		hasNext := len(nodes) > *args.First
+		if hasNext {
+			nodes, cursors = nodes[:*args.First], cursors[:*args.First]
+		}
+		return newConnection(nodes, cursors, args.After != nil, hasNext), nil
+	case args.Last != nil:
+		nodes, cursors, err := source.Before(ctx, args.Before, *args.Last+1)
+		if err == nil {
+			err = checkCursors(nodes, cursors)
+		}
+		if err != nil {
+			return nil, err
+		}
+		hasPrevious := len(nodes) > *args.Last
+		if hasPrevious {
+			skip := len(nodes) - *args.Last
+			nodes, cursors = nodes[skip:], cursors[skip:]
+		}
+		return newConnection(nodes, cursors, hasPrevious, args.Before != nil), nil
+	}
+	return nil, fmt.Errorf("ConnectionFromSource: Either first or last is required.")
+}
@@ -0,0 +1,192 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestConnectionDirective(t *testing.T) {
+	gql := `
+type Query {
+	users: [User!]! @connection
+	tags(prefix: String): [String] @connection
+}
+type User {
+	name: String
+}`
+
+	ctx, err := Generate(gql)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	if errs := ctx.Errors(); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	printed := PrintSchema(ctx)
+	for _, expected := range []string{
+		"type PageInfo {\n  endCursor: String\n  hasNextPage: Boolean!\n  hasPreviousPage: Boolean!\n  startCursor: String\n}",
+		"type Query {\n  tags(after: String, before: String, first: Int, last: Int, prefix: String): StringConnection\n" +
+			"  users(after: String, before: String, first: Int, last: Int): UserConnection!\n}",
+		"type UserConnection {\n  edges: [UserEdge!]!\n  pageInfo: PageInfo!\n}",
+		"type UserEdge {\n  cursor: String!\n  node: User!\n}",
+		"type StringEdge {\n  cursor: String!\n  node: String\n}",
+	} {
+		if !strings.Contains(printed, expected) {
+			t.Errorf("Expected printed schema to contain %q:\n%s", expected, printed)
+		}
+	}
+
+	users := []map[string]interface{}{{"name": "a"}, {"name": "b"}, {"name": "c"}}
+	ctx.SetResolver("Query", "users", func(p graphql.ResolveParams) (interface{}, error) {
+		return ConnectionFromSlice(users, NewConnectionArgs(p.Args))
+	})
+	schema, err := CreateSchemaFromContext(ctx)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	query := `{ users(first: 1, after: "` + OffsetCursor(0) + `") {
+		edges { node { name } }
+		pageInfo { hasNextPage hasPreviousPage }
+	} }`
+	r := graphql.Do(graphql.Params{Schema: schema, RequestString: query})
+	result, _ := json.Marshal(r)
+	expected := `{"data":{"users":{"edges":[{"node":{"name":"b"}}],"pageInfo":{"hasNextPage":true,"hasPreviousPage":true}}}}`
+	if string(result) != expected {
+		t.Errorf("Unexpected result: %s", result)
+	}
+
+	ctx, err = Generate(`type Query { user: String @connection }`)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	if errs := ctx.Errors(); len(errs) != 1 || !strings.HasSuffix(errs[0].Error(), "Directive @connection on Query.user: Only fields of list types can be connections.") {
+		t.Errorf("Unexpected errors: %v", errs)
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestConnectionFromSlice(t *testing.T) {
+	items := []string{"a", "b", "c", "d"}
+	after, before := OffsetCursor(0), OffsetCursor(3)
+	tests := []struct {
+		args     ConnectionArgs
+		expected string
+	}{
+		{ConnectionArgs{}, "[a b c d] false false"},
+		{ConnectionArgs{First: intPtr(2)}, "[a b] false true"},
+		{ConnectionArgs{Last: intPtr(2)}, "[c d] true false"},
+		{ConnectionArgs{After: &after, Before: &before}, "[b c] true true"},
+		{ConnectionArgs{After: &after, Last: intPtr(1)}, "[d] true false"},
+	}
+	for _, test := range tests {
+		conn, err := ConnectionFromSlice(items, test.args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var nodes []interface{}
+		for _, edge := range conn.Edges {
+			nodes = append(nodes, edge.Node)
+		}
+		result := fmt.Sprint(nodes, conn.PageInfo.HasPreviousPage, conn.PageInfo.HasNextPage)
+		if result != test.expected {
+			t.Errorf("Expected %s, got %s", test.expected, result)
+		}
+	}
+	if _, err := ConnectionFromSlice(items, ConnectionArgs{First: intPtr(-1)}); err == nil {
+		t.Errorf("Expected error for negative first")
+	}
+}
+
+// letters is a cursor source of the letters a to z with the letters as cursors.
+type letters struct{}
+
+func (letters) After(ctx context.Context, cursor *string, limit int) ([]interface{}, []string, error) {
+	start := byte('a')
+	if cursor != nil {
+		start = (*cursor)[0] + 1
+	}
+	var nodes []interface{}
+	var cursors []string
+	for c := start; c <= 'z' && len(nodes) < limit; c++ {
+		nodes = append(nodes, string(c))
+		cursors = append(cursors, string(c))
+	}
+	return nodes, cursors, nil
+}
+
+func (letters) Before(ctx context.Context, cursor *string, limit int) ([]interface{}, []string, error) {
+	end := byte('z')
+	if cursor != nil {
+		end = (*cursor)[0] - 1
+	}
+	var nodes []interface{}
+	var cursors []string
+	for c := end; c >= 'a' && len(nodes) < limit; c-- {
+		nodes = append([]interface{}{string(c)}, nodes...)
+		cursors = append([]string{string(c)}, cursors...)
+	}
+	return nodes, cursors, nil
+}
+
+func TestConnectionFromSource(t *testing.T) {
+	cursor := "x"
+	tests := []struct {
+		args     ConnectionArgs
+		expected string
+	}{
+		{ConnectionArgs{First: intPtr(2)}, "[a b] false true"},
+		{ConnectionArgs{First: intPtr(2), After: &cursor}, "[y z] true false"},
+		{ConnectionArgs{Last: intPtr(2), Before: &cursor}, "[v w] true true"},
+	}
+	for _, test := range tests {
+		conn, err := ConnectionFromSource(context.Background(), letters{}, test.args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var nodes []interface{}
+		for _, edge := range conn.Edges {
+			nodes = append(nodes, edge.Node)
+		}
+		result := fmt.Sprint(nodes, conn.PageInfo.HasPreviousPage, conn.PageInfo.HasNextPage)
+		if result != test.expected {
+			t.Errorf("Expected %s, got %s", test.expected, result)
+		}
+	}
+	if _, err := ConnectionFromSource(context.Background(), letters{}, ConnectionArgs{}); err == nil {
+		t.Errorf("Expected error without first and last")
+	}
+}
+
+// unevenLetters is a cursor source returning one cursor less than nodes.
+type unevenLetters struct{ letters }
+
+func (s unevenLetters) After(ctx context.Context, cursor *string, limit int) ([]interface{}, []string, error) {
+	nodes, cursors, err := s.letters.After(ctx, cursor, limit)
+	return nodes, cursors[1:], err
+}
+
+func (s unevenLetters) Before(ctx context.Context, cursor *string, limit int) ([]interface{}, []string, error) {
+	nodes, cursors, err := s.letters.Before(ctx, cursor, limit)
+	return nodes, cursors[1:], err
+}
+
+func TestConnectionFromSourceWithMissingCursors(t *testing.T) {
+	expected := "ConnectionFromSource: The source returned 3 nodes but 2 cursors."
+	for _, args := range []ConnectionArgs{{First: intPtr(2)}, {Last: intPtr(2)}} {
+		if _, err := ConnectionFromSource(context.Background(), unevenLetters{}, args); err == nil || err.Error() != expected {
+			t.Errorf("Expected %q, got %v", expected, err)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package generator
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/graphql-go/graphql"
+)
+
+// formats are the formats of @constraint(format:) and their checks.
+var formats = map[string]func(string) bool{
+	"email": func(s string) bool {
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	},
+	"uri": func(s string) bool {
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != ""
+	},
+	"uuid": regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`).MatchString,
+	"date-time": func(s string) bool {
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	},
+	"ipv4": func(s string) bool {
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil && !strings.Contains(s, ":")
+	},
+	"ipv6": func(s string) bool {
+		ip := net.ParseIP(s)
+		return ip != nil && strings.Contains(s, ":")
+	},
+}
+
+// constraint holds the arguments of a use of @constraint.
+type constraint struct {
+	minLength, maxLength *int
+	min, max             *float64
+	pattern              *regexp.Regexp
+	format               string
+}
+
+// Violation is a value violating a @constraint.
+type Violation struct {
+	// Path is the path of the value in the arguments, e.g. input.tags[1].
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// ConstraintError is the error of resolve calls with arguments violating constraints.
+type ConstraintError struct {
+	Violations []Violation
+}
+
+func (e *ConstraintError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = fmt.Sprintf("Invalid argument %s: %s", violation.Path, violation.Message)
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Extensions implements gqlerrors.ExtendedError.
+func (e *ConstraintError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":       "BAD_USER_INPUT",
+		"violations": e.Violations,
+	}
+}
+
+// Constraints registers ConstraintDirective as @constraint.
+func Constraints() Option {
+	return func(gen *Generator) {
+		gen.RegisterDirective("constraint", ConstraintDirective)
+	}
+}
+
+// ConstraintDirective implements @constraint(minLength: Int, maxLength: Int, pattern:
+// String, min: Float, max: Float, format: String) on arguments and input fields. The
+// arguments of a resolve call are checked before the resolver runs and all
+// violations are reported by a ConstraintError. Constraints on lists apply to their
+// items. The formats are email, uri, uuid, date-time, ipv4 and ipv6.
+func ConstraintDirective(site *DirectiveSite) error {
+	var typ graphql.Type
+	switch {
+	case site.Argument != nil:
+		typ = site.Argument.Type
+	case site.InputField != nil:
+		typ = site.InputField.Type
+	default:
+		return fmt.Errorf("Only arguments and input fields can be constrained.")
+	}
+	typ = namedType(typ)
+
+	c := &constraint{}
+	for name, value := range site.Args {
+		switch name {
+		case "minLength", "maxLength":
+			n, ok := value.(int)
+			if !ok {
+				return fmt.Errorf("%s must be an Int.", name)
+			}
+			if name == "minLength" {
+				c.minLength = &n
+			} else {
+				c.maxLength = &n
+			}
+		case "min", "max":
+			var f float64
+			switch n := value.(type) {
+			case int:
+				f = float64(n)
+			case float64:
+				f = n
+			default:
+				return fmt.Errorf("%s must be a number.", name)
+			}
+			if name == "min" {
+				c.min = &f
+			} else {
+				c.max = &f
+			}
+		case "pattern":
+			pattern, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("pattern must be a String.")
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("Invalid pattern: %s", err)
+			}
+			c.pattern = re
+		case "format":
+			format, ok := value.(string)
+			if _, known := formats[format]; !ok || !known {
+				return fmt.Errorf("Unknown format %v.", value)
+			}
+			c.format = format
+		}
+	}
+
+	numeric := typ == graphql.Int || typ == graphql.Float
+	textual := typ == graphql.String || typ == graphql.ID
+	if (c.min != nil || c.max != nil) && !numeric {
+		return fmt.Errorf("min and max require a value of type Int or Float, not %s.", typ)
+	}
+	if (c.minLength != nil || c.maxLength != nil || c.pattern != nil || c.format != "") && !textual {
+		return fmt.Errorf("minLength, maxLength, pattern and format require a value of type String or ID, not %s.", typ)
+	}
+
+	site.ctx.constraints[site.path()] = c
+	return nil
+}
+
+func namedType(typ graphql.Type) graphql.Type {
+	for {
+		switch t := typ.(type) {
+		case *graphql.NonNull:
+			typ = t.OfType
+		case *graphql.List:
+			typ = t.OfType
+		default:
+			return typ
+		}
+	}
+}
+
+// check returns the violations of value.
+func (c *constraint) check(path string, value interface{}) []Violation {
+	var messages []string
+	switch v := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(v)
+		if c.minLength != nil && length < *c.minLength {
+			messages = append(messages, fmt.Sprintf("Must be at least %d characters long.", *c.minLength))
+		}
+		if c.maxLength != nil && length > *c.maxLength {
+			messages = append(messages, fmt.Sprintf("Must be at most %d characters long.", *c.maxLength))
+		}
+		if c.pattern != nil && !c.pattern.MatchString(v) {
+			messages = append(messages, fmt.Sprintf("Must match %s.", c.pattern))
+		}
+		if c.format != "" && !formats[c.format](v) {
+			messages = append(messages, fmt.Sprintf("Must be formatted as %s.", c.format))
+		}
+	case int, float64:
+		n, ok := v.(float64)
+		if !ok {
+			n = float64(v.(int))
+		}
+		if c.min != nil && n < *c.min {
+			messages = append(messages, fmt.Sprintf("Must be at least %v.", *c.min))
+		}
+		if c.max != nil && n > *c.max {
+			messages = append(messages, fmt.Sprintf("Must be at most %v.", *c.max))
+		}
+	}
+
+	violations := make([]Violation, len(messages))
+	for i, message := range messages {
+		violations[i] = Violation{Path: path, Message: message}
+	}
+	return violations
+}
+
+// inheritConstraints copies the constraints of the arguments of an interface field to
+// the field of an implementing object, unless the object declares its own.
+func (g *Context) inheritConstraints(ifaceName, typeName, fieldName string) {
+	prefix := ifaceName + "." + fieldName + "("
+	for key, c := range g.constraints {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		objectKey := typeName + "." + fieldName + strings.TrimPrefix(key, ifaceName+"."+fieldName)
+		if _, ok := g.constraints[objectKey]; !ok {
+			g.constraints[objectKey] = c
+		}
+	}
+}
+
+// hasConstraints reports whether the arguments of a field, or the input fields of
+// their types, have constraints.
+func (g *Context) hasConstraints(typeName string, fieldDef *graphql.FieldDefinition) bool {
+	visited := make(map[string]bool)
+	for _, arg := range fieldDef.Args {
+		if g.constraints[fmt.Sprintf("%s.%s(%s:)", typeName, fieldDef.Name, arg.Name())] != nil {
+			return true
+		}
+		if g.inputHasConstraints(namedType(arg.Type), visited) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Context) inputHasConstraints(typ graphql.Type, visited map[string]bool) bool {
+	input, ok := typ.(*graphql.InputObject)
+	if !ok || visited[input.Name()] {
+		return false
+	}
+	visited[input.Name()] = true
+	for fieldName, field := range input.Fields() {
+		if g.constraints[input.Name()+"."+fieldName] != nil || g.inputHasConstraints(namedType(field.Type), visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkConstraints is the middleware checking the arguments of resolve calls against
+// the constraints of the context.
+func (g *Context) checkConstraints(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		object, ok := p.Info.ParentType.(*graphql.Object)
+		if !ok {
+			return next(p)
+		}
+		fieldDef, ok := object.Fields()[p.Info.FieldName]
+		if !ok || len(fieldDef.Args) == 0 {
+			return next(p)
+		}
+
+		args := make(map[string]*graphql.Argument, len(fieldDef.Args))
+		for _, arg := range fieldDef.Args {
+			args[arg.Name()] = arg
+		}
+		var violations []Violation
+		for _, argName := range sortedKeys(args) {
+			arg := args[argName]
+			key := fmt.Sprintf("%s.%s(%s:)", object.Name(), p.Info.FieldName, arg.Name())
+			violations = append(violations, g.violations(g.constraints[key], arg.Type, arg.Name(), p.Args[arg.Name()])...)
+		}
+		if len(violations) > 0 {
+			return nil, &ConstraintError{Violations: violations}
+		}
+		return next(p)
+	}
+}
+
+// violations returns the violations of value of type typ and of the input fields it holds.
+func (g *Context) violations(c *constraint, typ graphql.Type, path string, value interface{}) []Violation {
+	if value == nil {
+		return nil
+	}
+	switch t := typ.(type) {
+	case *graphql.NonNull:
+		return g.violations(c, t.OfType, path, value)
+	case *graphql.List:
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil
+		}
+		var violations []Violation
+		for i, item := range items {
+			violations = append(violations, g.violations(c, t.OfType, fmt.Sprintf("%s[%d]", path, i), item)...)
+		}
+		return violations
+	case *graphql.InputObject:
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		var violations []Violation
+		inputFields := t.Fields()
+		for _, fieldName := range sortedKeys(inputFields) {
+			fieldConstraint := g.constraints[t.Name()+"."+fieldName]
+			violations = append(violations, g.violations(fieldConstraint, inputFields[fieldName].Type, path+"."+fieldName, fields[fieldName])...)
+		}
+		return violations
+	}
+	if c == nil {
+		return nil
+	}
+	return c.check(path, value)
+}
@@ -0,0 +1,121 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestConstraintDirective(t *testing.T) {
+	gql := `
+directive @constraint(minLength: Int, maxLength: Int, pattern: String, min: Float, max: Float, format: String) on ARGUMENT_DEFINITION | INPUT_FIELD_DEFINITION
+
+input UserInput {
+	name: String! @constraint(minLength: 1, maxLength: 5)
+	email: String @constraint(format: "email")
+	tags: [String] @constraint(pattern: "^[a-z]+$")
+}
+type Query {
+	a: String
+}
+type Mutation {
+	createUser(input: UserInput!, age: Int @constraint(min: 0, max: 150)): String
+}`
+
+	ctx, err := New(Constraints()).Generate(gql)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	if errs := ctx.Errors(); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+	ctx.SetResolver("Mutation", "createUser", func(p graphql.ResolveParams) (interface{}, error) {
+		return "created", nil
+	})
+	schema, err := CreateSchemaFromContext(ctx)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+
+	tests := map[string]string{
+		`mutation { createUser(input: {name: "Ada", email: "ada@example.com", tags: ["a"]}, age: 36) }`: `{"data":{"createUser":"created"}}`,
+		`mutation { createUser(input: {name: "", email: "ada", tags: ["a", "B"]}, age: -1) }`:           `{"data":{"createUser":null},"errors":[{"message":"Invalid argument age: Must be at least 0.\nInvalid argument input.email: Must be formatted as email.\nInvalid argument input.name: Must be at least 1 characters long.\nInvalid argument input.tags[1]: Must match ^[a-z]+$.","locations":[{"line":1,"column":12}],"path":["createUser"],"extensions":{"code":"BAD_USER_INPUT","violations":[{"path":"age","message":"Must be at least 0."},{"path":"input.email","message":"Must be formatted as email."},{"path":"input.name","message":"Must be at least 1 characters long."},{"path":"input.tags[1]","message":"Must match ^[a-z]+$."}]}}]}`,
+	}
+	for query, expected := range tests {
+		r := graphql.Do(graphql.Params{Schema: schema, RequestString: query})
+		result, _ := json.Marshal(r)
+		if string(result) != expected {
+			t.Errorf("Unexpected result of %s:\n%s", query, result)
+		}
+	}
+}
+
+func TestConstraintDirectiveErrors(t *testing.T) {
+	tests := map[string]string{
+		`type Query { a(n: Int @constraint(minLength: 1)): String }`:       "Directive @constraint on Query.a(n:): minLength, maxLength, pattern and format require a value of type String or ID, not Int.",
+		`type Query { a(s: String @constraint(format: "phone")): String }`: "Directive @constraint on Query.a(s:): Unknown format phone.",
+		`type Query { a(s: String @constraint(pattern: "(")): String }`:    "Directive @constraint on Query.a(s:): Invalid pattern: error parsing regexp: missing closing ): `(`",
+	}
+	for gql, expected := range tests {
+		ctx, err := New(Constraints()).Generate(gql)
+		if err != nil {
+			fmt.Print(err)
+			t.FailNow()
+		}
+		var message string
+		if errs := ctx.Errors(); len(errs) > 0 {
+			message = errs[0].(*SchemaError).Message
+		}
+		if message != expected {
+			t.Errorf("Expected error %q, got %q", expected, message)
+		}
+	}
+}
+
+func TestConstraintsOfInterfaceFields(t *testing.T) {
+	gql := `
+directive @constraint(minLength: Int, maxLength: Int, pattern: String, min: Float, max: Float, format: String) on ARGUMENT_DEFINITION | INPUT_FIELD_DEFINITION
+
+interface Named {
+	name(length: Int @constraint(min: 1)): String
+}
+type User implements Named {
+	name(length: Int): String
+	age: Int
+}
+type Query {
+	user: User
+	plain(length: Int): String
+}`
+
+	ctx, err := New(Constraints()).Generate(gql)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	ctx.SetResolver("Query", "user", func(p graphql.ResolveParams) (interface{}, error) {
+		return map[string]interface{}{"name": "Ada", "age": 36}, nil
+	})
+	ctx.SetResolveType("Named", func(p graphql.ResolveTypeParams) *graphql.Object {
+		return ctx.Object("User")
+	})
+	schema, err := CreateSchemaFromContext(ctx)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+
+	r := graphql.Do(graphql.Params{Schema: schema, RequestString: `{ user { name(length: 0) } }`})
+	if len(r.Errors) != 1 || r.Errors[0].Message != "Invalid argument length: Must be at least 1." {
+		t.Errorf("Expected the constraint of Named.name to apply to User.name, got %v", r.Errors)
+	}
+	for _, field := range []*graphql.FieldDefinition{ctx.Object("User").Fields()["age"], ctx.Object("Query").Fields()["plain"]} {
+		if _, wrapped := ctx.resolvers[field]; wrapped {
+			t.Errorf("Expected %s without constraints not to be wrapped", field.Name)
+		}
+	}
+}
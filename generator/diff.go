@@ -0,0 +1,378 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Severity classifies a change between two versions of a schema.
+type Severity int
+
+const (
+	// Safe changes do not affect existing clients.
+	Safe Severity = iota
+
+	// Dangerous changes keep existing queries valid but may change their results,
+	// e.g. a new enum value clients do not handle.
+	Dangerous
+
+	// Breaking changes make existing queries invalid or their results unexpected.
+	Breaking
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Safe:
+		return "SAFE"
+	case Dangerous:
+		return "DANGEROUS"
+	case Breaking:
+		return "BREAKING"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// ChangeKind tells what happened to the changed element.
+type ChangeKind int
+
+const (
+	// Changed elements exist in both versions, e.g. a field of another type.
+	Changed ChangeKind = iota
+	Added
+	Removed
+	Deprecated
+)
+
+// Change is a difference between two versions of a schema.
+type Change struct {
+	Kind     ChangeKind
+	Severity Severity
+
+	// Path is the changed element, e.g. User, User.name, Query.user(id:), Role.ADMIN or
+	// @cached.
+	Path    string
+	Message string
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s", c.Severity, c.Message)
+}
+
+// Diff compares the types, fields, arguments, input fields, enum values, union
+// members, interfaces and directive definitions of two contexts and returns the
+// changes ordered by path. Outputs may become stricter and inputs laxer, e.g. a
+// nullable field made non-null is safe while a nullable argument made non-null is
+// breaking.
+func Diff(old, new *Context) []Change {
+	d := &differ{}
+	oldNames, newNames := typeSet(old), typeSet(new)
+	for _, name := range sortedKeys(oldNames) {
+		if !newNames[name] {
+			d.add(Removed, Breaking, name, "Type %s was removed.", name)
+			continue
+		}
+		oldType, _ := old.GetObject(name)
+		newType, _ := new.GetObject(name)
+		if kindName(oldType) != kindName(newType) {
+			d.add(Changed, Breaking, name, "Type %s changed from %s to %s.", name, kindName(oldType), kindName(newType))
+			continue
+		}
+		switch oldType.(type) {
+		case *graphql.Object:
+			oldConfig, newConfig := old.objectConfigs[name], new.objectConfigs[name]
+			d.interfaces(name, oldConfig.Interfaces, newConfig.Interfaces)
+			d.fields(name, configFields(oldConfig.Fields), configFields(newConfig.Fields))
+		case *graphql.Interface:
+			d.fields(name, configFields(old.interfaceConfigs[name].Fields), configFields(new.interfaceConfigs[name].Fields))
+		case *graphql.Union:
+			d.members(name, old.unionConfigs[name].Types, new.unionConfigs[name].Types)
+		case *graphql.Enum:
+			d.enumValues(name, old.enumValues(name), new.enumValues(name))
+		case *graphql.InputObject:
+			oldFields, _ := old.inputConfigs[name].Fields.(graphql.InputObjectConfigFieldMap)
+			newFields, _ := new.inputConfigs[name].Fields.(graphql.InputObjectConfigFieldMap)
+			d.inputFields(name, oldFields, newFields)
+		}
+	}
+	for _, name := range sortedKeys(newNames) {
+		if !oldNames[name] {
+			d.add(Added, Safe, name, "Type %s was added.", name)
+		}
+	}
+	d.directives(old.directiveConfigs, new.directiveConfigs)
+
+	sort.SliceStable(d.changes, func(i, j int) bool {
+		return d.changes[i].Path < d.changes[j].Path
+	})
+	return d.changes
+}
+
+type differ struct {
+	changes []Change
+}
+
+func (d *differ) add(kind ChangeKind, severity Severity, path, format string, args ...interface{}) {
+	d.changes = append(d.changes, Change{Kind: kind, Severity: severity, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func typeSet(ctx *Context) map[string]bool {
+	names := make(map[string]bool)
+	for _, name := range ctx.TypeNames() {
+		names[name] = true
+	}
+	return names
+}
+
+func kindName(typ graphql.Type) string {
+	switch typ.(type) {
+	case *graphql.Scalar:
+		return "a scalar"
+	case *graphql.Object:
+		return "an object"
+	case *graphql.Interface:
+		return "an interface"
+	case *graphql.Union:
+		return "a union"
+	case *graphql.Enum:
+		return "an enum"
+	case *graphql.InputObject:
+		return "an input object"
+	}
+	return "an unknown type"
+}
+
+func (d *differ) fields(typeName string, oldFields, newFields graphql.Fields) {
+	for _, fieldName := range sortedKeys(oldFields) {
+		path := typeName + "." + fieldName
+		if _, ok := newFields[fieldName]; !ok {
+			d.add(Removed, Breaking, path, "Field %s was removed.", path)
+			continue
+		}
+		oldField, newField := oldFields[fieldName], newFields[fieldName]
+		if oldType, newType := oldField.Type.String(), newField.Type.String(); oldType != newType {
+			severity := Breaking
+			if safeOutputChange(oldField.Type, newField.Type) {
+				severity = Safe
+			}
+			d.add(Changed, severity, path, "Field %s changed type from %s to %s.", path, oldType, newType)
+		}
+		if oldField.DeprecationReason == "" && newField.DeprecationReason != "" {
+			d.add(Deprecated, Safe, path, "Field %s was deprecated.", path)
+		}
+		d.args(path, oldField.Args, newField.Args)
+	}
+	for _, fieldName := range sortedKeys(newFields) {
+		if _, ok := oldFields[fieldName]; !ok {
+			path := typeName + "." + fieldName
+			d.add(Added, Safe, path, "Field %s was added.", path)
+		}
+	}
+}
+
+func (d *differ) args(fieldPath string, oldArgs, newArgs graphql.FieldConfigArgument) {
+	for _, argName := range sortedKeys(oldArgs) {
+		path := fmt.Sprintf("%s(%s:)", fieldPath, argName)
+		newArg, ok := newArgs[argName]
+		if !ok {
+			d.add(Removed, Breaking, path, "Argument %s was removed.", path)
+			continue
+		}
+		oldArg := oldArgs[argName]
+		d.input(path, "Argument", oldArg.Type, newArg.Type, oldArg.DefaultValue, newArg.DefaultValue)
+	}
+	for _, argName := range sortedKeys(newArgs) {
+		if _, ok := oldArgs[argName]; !ok {
+			path := fmt.Sprintf("%s(%s:)", fieldPath, argName)
+			d.added(path, "argument", newArgs[argName].Type, newArgs[argName].DefaultValue)
+		}
+	}
+}
+
+func (d *differ) inputFields(typeName string, oldFields, newFields graphql.InputObjectConfigFieldMap) {
+	for _, fieldName := range sortedKeys(oldFields) {
+		path := typeName + "." + fieldName
+		newField, ok := newFields[fieldName]
+		if !ok {
+			d.add(Removed, Breaking, path, "Input field %s was removed.", path)
+			continue
+		}
+		oldField := oldFields[fieldName]
+		d.input(path, "Input field", oldField.Type, newField.Type, oldField.DefaultValue, newField.DefaultValue)
+	}
+	for _, fieldName := range sortedKeys(newFields) {
+		if _, ok := oldFields[fieldName]; !ok {
+			path := typeName + "." + fieldName
+			d.added(path, "input field", newFields[fieldName].Type, newFields[fieldName].DefaultValue)
+		}
+	}
+}
+
+// input compares an argument or input field present in both versions.
+func (d *differ) input(path, what string, oldType, newType graphql.Type, oldDefault, newDefault interface{}) {
+	if oldType.String() != newType.String() {
+		severity := Breaking
+		if safeInputChange(oldType, newType) {
+			severity = Safe
+		}
+		d.add(Changed, severity, path, "%s %s changed type from %s to %s.", what, path, oldType, newType)
+	}
+	if oldDefault != nil || newDefault != nil {
+		if printed := printValue(oldDefault); printed != printValue(newDefault) {
+			d.add(Changed, Dangerous, path, "%s %s changed default value from %s to %s.", what, path, printed, printValue(newDefault))
+		}
+	}
+}
+
+// added reports an argument or input field which is new. Required ones break
+// existing queries, optional ones may change their results.
+func (d *differ) added(path, what string, typ graphql.Type, defaultValue interface{}) {
+	if _, nonNull := typ.(*graphql.NonNull); nonNull && defaultValue == nil {
+		d.add(Added, Breaking, path, "Required %s %s was added.", what, path)
+		return
+	}
+	d.add(Added, Dangerous, path, "Optional %s %s was added.", what, path)
+}
+
+// safeOutputChange reports whether values of newType are valid values of oldType.
+func safeOutputChange(oldType, newType graphql.Type) bool {
+	switch o := oldType.(type) {
+	case *graphql.List:
+		switch n := newType.(type) {
+		case *graphql.List:
+			return safeOutputChange(o.OfType, n.OfType)
+		case *graphql.NonNull:
+			return safeOutputChange(oldType, n.OfType)
+		}
+		return false
+	case *graphql.NonNull:
+		n, ok := newType.(*graphql.NonNull)
+		return ok && safeOutputChange(o.OfType, n.OfType)
+	}
+	switch n := newType.(type) {
+	case *graphql.NonNull:
+		return safeOutputChange(oldType, n.OfType)
+	case *graphql.List:
+		return false
+	}
+	return oldType.Name() == newType.Name()
+}
+
+// safeInputChange reports whether values of oldType are valid values of newType.
+func safeInputChange(oldType, newType graphql.Type) bool {
+	switch o := oldType.(type) {
+	case *graphql.List:
+		n, ok := newType.(*graphql.List)
+		return ok && safeInputChange(o.OfType, n.OfType)
+	case *graphql.NonNull:
+		if n, ok := newType.(*graphql.NonNull); ok {
+			return safeInputChange(o.OfType, n.OfType)
+		}
+		return safeInputChange(o.OfType, newType)
+	}
+	switch newType.(type) {
+	case *graphql.NonNull, *graphql.List:
+		return false
+	}
+	return oldType.Name() == newType.Name()
+}
+
+func (d *differ) interfaces(typeName string, oldIfaces, newIfaces interface{}) {
+	oldNames, newNames := interfaceNames(oldIfaces), interfaceNames(newIfaces)
+	for _, name := range sortedKeys(oldNames) {
+		if !newNames[name] {
+			d.add(Changed, Breaking, typeName, "Object %s no longer implements %s.", typeName, name)
+		}
+	}
+	for _, name := range sortedKeys(newNames) {
+		if !oldNames[name] {
+			d.add(Changed, Dangerous, typeName, "Object %s now implements %s.", typeName, name)
+		}
+	}
+}
+
+func interfaceNames(ifaces interface{}) map[string]bool {
+	names := make(map[string]bool)
+	list, _ := ifaces.([]*graphql.Interface)
+	for _, iface := range list {
+		names[iface.Name()] = true
+	}
+	return names
+}
+
+func (d *differ) members(typeName string, oldTypes, newTypes interface{}) {
+	oldNames, newNames := memberNames(oldTypes), memberNames(newTypes)
+	for _, name := range sortedKeys(oldNames) {
+		if !newNames[name] {
+			d.add(Changed, Breaking, typeName, "Member %s was removed from union %s.", name, typeName)
+		}
+	}
+	for _, name := range sortedKeys(newNames) {
+		if !oldNames[name] {
+			d.add(Changed, Dangerous, typeName, "Member %s was added to union %s.", name, typeName)
+		}
+	}
+}
+
+func memberNames(types interface{}) map[string]bool {
+	names := make(map[string]bool)
+	list, _ := types.([]*graphql.Object)
+	for _, ob := range list {
+		names[ob.Name()] = true
+	}
+	return names
+}
+
+func (d *differ) enumValues(typeName string, oldValues, newValues []enumValue) {
+	newByName := make(map[string]enumValue, len(newValues))
+	for _, value := range newValues {
+		newByName[value.Name] = value
+	}
+	oldByName := make(map[string]bool, len(oldValues))
+	for _, value := range oldValues {
+		oldByName[value.Name] = true
+		path := typeName + "." + value.Name
+		newValue, ok := newByName[value.Name]
+		if !ok {
+			d.add(Removed, Breaking, path, "Enum value %s was removed.", path)
+			continue
+		}
+		if value.DeprecationReason == "" && newValue.DeprecationReason != "" {
+			d.add(Deprecated, Safe, path, "Enum value %s was deprecated.", path)
+		}
+	}
+	for _, value := range newValues {
+		if !oldByName[value.Name] {
+			path := typeName + "." + value.Name
+			d.add(Added, Dangerous, path, "Enum value %s was added.", path)
+		}
+	}
+}
+
+func (d *differ) directives(oldConfigs, newConfigs map[string]graphql.DirectiveConfig) {
+	for _, name := range sortedKeys(oldConfigs) {
+		path := "@" + name
+		newConfig, ok := newConfigs[name]
+		if !ok {
+			d.add(Removed, Breaking, path, "Directive %s was removed.", path)
+			continue
+		}
+		oldConfig := oldConfigs[name]
+		newLocations := make(map[string]bool)
+		for _, location := range newConfig.Locations {
+			newLocations[location] = true
+		}
+		for _, location := range oldConfig.Locations {
+			if !newLocations[location] {
+				d.add(Changed, Breaking, path, "Location %s was removed from directive %s.", location, path)
+			}
+		}
+		d.args(path, oldConfig.Args, newConfig.Args)
+	}
+	for _, name := range sortedKeys(newConfigs) {
+		if _, ok := oldConfigs[name]; !ok {
+			d.add(Added, Safe, "@"+name, "Directive @%s was added.", name)
+		}
+	}
+}
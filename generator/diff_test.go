@@ -0,0 +1,104 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	ctxs := generateAll(t, `
+directive @cached(ttl: Int) on FIELD_DEFINITION | OBJECT
+interface Node {
+	id: ID!
+}
+type Query {
+	user(id: ID!, verbose: Boolean = false): User
+	users(filter: UserFilter): [User]
+	legacy: String
+	count: Int!
+	tags: [String]
+}
+type User implements Node {
+	id: ID!
+	name: String
+	role: Role
+}
+type Bot {
+	id: ID!
+}
+union Actor = User | Bot
+enum Role {
+	ADMIN
+	USER
+}
+input UserFilter {
+	name: String
+	role: Role
+}
+scalar Date`, `
+directive @cached(ttl: Int, scope: String) on FIELD_DEFINITION
+interface Node {
+	id: ID!
+}
+type Query {
+	user(id: ID, verbose: Boolean = true, limit: Int!): User
+	users(filter: UserFilter): [User!]
+	legacy: String @deprecated
+	count: Int
+	tags: String
+	me: User
+}
+type User implements Node {
+	id: ID!
+	name: String!
+	role: Role
+}
+type Bot implements Node {
+	id: ID!
+}
+union Actor = User
+enum Role {
+	USER
+	GUEST
+}
+input UserFilter {
+	name: String!
+	role: Role
+	active: Boolean
+}
+input Date {
+	day: Int
+}`)
+
+	var changes []string
+	for _, change := range Diff(ctxs[0], ctxs[1]) {
+		changes = append(changes, change.String())
+	}
+	expected := []string{
+		"BREAKING: Location OBJECT was removed from directive @cached.",
+		"DANGEROUS: Optional argument @cached(scope:) was added.",
+		"BREAKING: Member Bot was removed from union Actor.",
+		"DANGEROUS: Object Bot now implements Node.",
+		"BREAKING: Type Date changed from a scalar to an input object.",
+		"BREAKING: Field Query.count changed type from Int! to Int.",
+		"SAFE: Field Query.legacy was deprecated.",
+		"SAFE: Field Query.me was added.",
+		"BREAKING: Field Query.tags changed type from [String] to String.",
+		"SAFE: Argument Query.user(id:) changed type from ID! to ID.",
+		"BREAKING: Required argument Query.user(limit:) was added.",
+		"DANGEROUS: Argument Query.user(verbose:) changed default value from false to true.",
+		"SAFE: Field Query.users changed type from [User] to [User!].",
+		"BREAKING: Enum value Role.ADMIN was removed.",
+		"DANGEROUS: Enum value Role.GUEST was added.",
+		"SAFE: Field User.name changed type from String to String!.",
+		"DANGEROUS: Optional input field UserFilter.active was added.",
+		"BREAKING: Input field UserFilter.name changed type from String to String!.",
+	}
+	if strings.Join(changes, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected changes:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(changes, "\n"))
+	}
+
+	if changes := Diff(ctxs[0], ctxs[0]); len(changes) > 0 {
+		t.Errorf("Expected no changes between equal schemas, got %v", changes)
+	}
+}
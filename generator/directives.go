@@ -0,0 +1,320 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// DirectiveHandler implements a schema directive. It is called for each use of the
+// directive while the annotated definition is generated and may change its config.
+// Returning an error fails the generation of the definition.
+type DirectiveHandler func(site *DirectiveSite) error
+
+// DirectiveSite is a use of a directive. Exactly one of the config fields is set,
+// according to Location. Directives on type extensions are only applied to the
+// fields they add.
+type DirectiveSite struct {
+	// Name is the name of the directive without @.
+	Name string
+
+	// Location is one of the graphql.DirectiveLocation constants.
+	Location string
+
+	// Args holds the argument values of the use, including the defaults of the
+	// directive definition.
+	Args map[string]interface{}
+
+	// TypeName is the name of the annotated type or the type declaring the
+	// annotated field, argument, enum value or input field.
+	TypeName string
+
+	// FieldName is the name of the annotated field, input field or enum value or
+	// of the field declaring the annotated argument.
+	FieldName string
+
+	// ArgName is the name of the annotated argument.
+	ArgName string
+
+	Object      *graphql.ObjectConfig
+	Interface   *graphql.InterfaceConfig
+	Union       *graphql.UnionConfig
+	Enum        *graphql.EnumConfig
+	Scalar      *graphql.ScalarConfig
+	InputObject *graphql.InputObjectConfig
+	Field       *graphql.Field
+	Argument    *graphql.ArgumentConfig
+	EnumValue   *graphql.EnumValueConfig
+	InputField  *graphql.InputObjectFieldConfig
+
+	ctx *Context
+}
+
+// Wrap wraps the resolve function of the annotated field, of the field declaring the
+// annotated argument or of all fields of the annotated object or interface with
+// middleware. It is applied by CreateSchemaFromContext to the final resolve
+// functions, so resolvers set later by Extend or SetResolver are wrapped as well.
+// Other locations have no resolvers and return an error.
+func (s *DirectiveSite) Wrap(middleware FieldMiddleware) error {
+	key := s.TypeName
+	switch s.Location {
+	case graphql.DirectiveLocationFieldDefinition, graphql.DirectiveLocationArgumentDefinition:
+		key += "." + s.FieldName
+	case graphql.DirectiveLocationObject, graphql.DirectiveLocationInterface:
+	default:
+		return fmt.Errorf("There are no resolvers to wrap on %s.", s.Location)
+	}
+	s.ctx.pending[key] = append(s.ctx.pending[key], middleware)
+	return nil
+}
+
+// commitMiddlewares keeps the middlewares registered while generating a definition.
+// Those of failed attempts are dropped, as the definition is generated again.
+func (g *Context) commitMiddlewares() {
+	for key, middlewares := range g.pending {
+		g.middlewares[key] = append(g.middlewares[key], middlewares...)
+	}
+	g.pending = make(map[string][]FieldMiddleware)
+}
+
+func (s *DirectiveSite) path() string {
+	path := s.TypeName
+	if s.FieldName != "" {
+		path += "." + s.FieldName
+	}
+	if s.ArgName != "" {
+		path += "(" + s.ArgName + ":)"
+	}
+	return path
+}
+
+// RegisterDirective registers the handler implementing the directive with the given
+// name. A handler registered before for the name is replaced.
+func (gen *Generator) RegisterDirective(name string, handler DirectiveHandler) *Generator {
+	gen.directives[name] = handler
+	return gen
+}
+
+// deprecatedDirective implements @deprecated(reason: String) on fields and enum values.
+func deprecatedDirective(site *DirectiveSite) error {
+	reason, ok := site.Args["reason"].(string)
+	if !ok {
+		reason = graphql.DefaultDeprecationReason
+	}
+	switch {
+	case site.Field != nil:
+		site.Field.DeprecationReason = reason
+	case site.EnumValue != nil:
+		site.EnumValue.DeprecationReason = reason
+	}
+	return nil
+}
+
+// directiveLocations are the locations a directive definition may name.
+var directiveLocations = map[string]bool{
+	graphql.DirectiveLocationQuery:                true,
+	graphql.DirectiveLocationMutation:             true,
+	graphql.DirectiveLocationSubscription:         true,
+	graphql.DirectiveLocationField:                true,
+	graphql.DirectiveLocationFragmentDefinition:   true,
+	graphql.DirectiveLocationFragmentSpread:       true,
+	graphql.DirectiveLocationInlineFragment:       true,
+	graphql.DirectiveLocationSchema:               true,
+	graphql.DirectiveLocationScalar:               true,
+	graphql.DirectiveLocationObject:               true,
+	graphql.DirectiveLocationFieldDefinition:      true,
+	graphql.DirectiveLocationArgumentDefinition:   true,
+	graphql.DirectiveLocationInterface:            true,
+	graphql.DirectiveLocationUnion:                true,
+	graphql.DirectiveLocationEnum:                 true,
+	graphql.DirectiveLocationEnumValue:            true,
+	graphql.DirectiveLocationInputObject:          true,
+	graphql.DirectiveLocationInputFieldDefinition: true,
+}
+
+func checkDirectiveLocations(locations []string) error {
+	for _, location := range locations {
+		if !directiveLocations[location] {
+			return fmt.Errorf("Unknown directive location %s.", location)
+		}
+	}
+	return nil
+}
+
+// schemaDirectives returns the directives of the schema: the directives specified by
+// GraphQL followed by the directives defined in the SDL in alphabetical order. SDL
+// definitions of specified directives are ignored.
+func (g *Context) schemaDirectives() []*graphql.Directive {
+	directives := append([]*graphql.Directive{}, graphql.SpecifiedDirectives...)
+	specified := make(map[string]bool)
+	for _, directive := range graphql.SpecifiedDirectives {
+		specified[directive.Name] = true
+	}
+	for _, name := range sortedKeys(g.directives) {
+		if !specified[name] {
+			directives = append(directives, g.directives[name])
+		}
+	}
+	return directives
+}
+
+// directiveDefinitions collects the directive definitions of the document of ctx.
+func directiveDefinitions(astDoc *ast.Document) map[string]*ast.DirectiveDefinition {
+	defs := make(map[string]*ast.DirectiveDefinition)
+	for _, def := range astDoc.Definitions {
+		if ddef, ok := def.(*ast.DirectiveDefinition); ok {
+			defs[ddef.Name.Value] = ddef
+		}
+	}
+	return defs
+}
+
+// applyDirectives validates the uses of directives against their definitions and
+// calls the handlers of the directives with site.
+func (g *Context) applyDirectives(directives []*ast.Directive, site DirectiveSite) error {
+	for _, directive := range directives {
+		name := directive.Name.Value
+		args, err := g.directiveArgs(directive, site.Location)
+		if err != nil {
+			return fmt.Errorf("Directive @%s on %s: %s", name, site.path(), err)
+		}
+		handler, ok := g.generator.directives[name]
+		if !ok {
+			continue
+		}
+		use := site
+		use.Name = name
+		use.Args = args
+		use.ctx = g
+		if err := handler(&use); err != nil {
+			return fmt.Errorf("Directive @%s on %s: %s", name, site.path(), err)
+		}
+	}
+	return nil
+}
+
+// directiveArgs returns the argument values of a use of a directive. Uses of declared
+// directives are checked against the declaration.
+func (g *Context) directiveArgs(directive *ast.Directive, location string) (map[string]interface{}, error) {
+	args := make(map[string]interface{}, len(directive.Arguments))
+	for _, arg := range directive.Arguments {
+		args[arg.Name.Value] = astValue(arg.Value)
+	}
+
+	def, ok := g.directiveDefs[directive.Name.Value]
+	if !ok {
+		return args, nil
+	}
+
+	allowed := false
+	for _, loc := range def.Locations {
+		allowed = allowed || loc.Value == location
+	}
+	if !allowed {
+		return nil, fmt.Errorf("Directive may not be used on %s.", location)
+	}
+
+	declared := make(map[string]*ast.InputValueDefinition, len(def.Arguments))
+	for _, argDef := range def.Arguments {
+		declared[argDef.Name.Value] = argDef
+	}
+	for _, arg := range directive.Arguments {
+		argDef, ok := declared[arg.Name.Value]
+		if !ok {
+			return nil, fmt.Errorf("Unknown argument %s.", arg.Name.Value)
+		}
+		if !g.validLiteral(argDef.Type, arg.Value) {
+			return nil, fmt.Errorf("Argument %s: Expected a value of type %s.", arg.Name.Value, typeString(argDef.Type))
+		}
+	}
+	for _, argDef := range def.Arguments {
+		name := argDef.Name.Value
+		if _, ok := args[name]; ok {
+			continue
+		}
+		if argDef.DefaultValue != nil {
+			args[name] = astValue(argDef.DefaultValue)
+		} else if _, nonNull := argDef.Type.(*ast.NonNull); nonNull {
+			return nil, fmt.Errorf("Argument %s of type %s is required.", name, typeString(argDef.Type))
+		}
+	}
+	return args, nil
+}
+
+// validLiteral reports whether value is a valid literal of typ.
+func (g *Context) validLiteral(typ ast.Type, value ast.Value) bool {
+	switch typ.(type) {
+	case *ast.NonNull:
+		return g.validLiteral(typ.(*ast.NonNull).Type, value)
+	case *ast.List:
+		list, ok := value.(*ast.ListValue)
+		if !ok {
+			// A single value is coerced to a list
+			return g.validLiteral(typ.(*ast.List).Type, value)
+		}
+		for _, item := range list.Values {
+			if !g.validLiteral(typ.(*ast.List).Type, item) {
+				return false
+			}
+		}
+		return true
+	}
+
+	name := typ.(*ast.Named).Name.Value
+	if _, ok := g.scalarConfigs[name]; ok {
+		// Custom scalars define their own literals
+		return true
+	}
+	switch value.(type) {
+	case *ast.IntValue:
+		return name == "Int" || name == "Float" || name == "ID"
+	case *ast.FloatValue:
+		return name == "Float"
+	case *ast.StringValue:
+		return name == "String" || name == "ID"
+	case *ast.BooleanValue:
+		return name == "Boolean"
+	case *ast.EnumValue:
+		_, ok := g.enumConfigs[name].Values[value.(*ast.EnumValue).Value]
+		return ok
+	case *ast.ObjectValue:
+		_, ok := g.inputConfigs[name]
+		return ok
+	}
+	return false
+}
+
+// astValue returns the Go value of a literal. Enum values are returned as their names.
+func astValue(value ast.Value) interface{} {
+	switch value.(type) {
+	case *ast.IntValue:
+		if i, err := strconv.Atoi(value.(*ast.IntValue).Value); err == nil {
+			return i
+		}
+	case *ast.FloatValue:
+		if f, err := strconv.ParseFloat(value.(*ast.FloatValue).Value, 64); err == nil {
+			return f
+		}
+	case *ast.StringValue:
+		return value.(*ast.StringValue).Value
+	case *ast.BooleanValue:
+		return value.(*ast.BooleanValue).Value
+	case *ast.EnumValue:
+		return value.(*ast.EnumValue).Value
+	case *ast.ListValue:
+		var list []interface{}
+		for _, item := range value.(*ast.ListValue).Values {
+			list = append(list, astValue(item))
+		}
+		return list
+	case *ast.ObjectValue:
+		object := make(map[string]interface{})
+		for _, field := range value.(*ast.ObjectValue).Fields {
+			object[field.Name.Value] = astValue(field.Value)
+		}
+		return object
+	}
+	return nil
+}
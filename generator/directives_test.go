@@ -0,0 +1,210 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestRegisterDirective(t *testing.T) {
+	gql := `
+directive @upper(exclaim: Boolean = false) on FIELD_DEFINITION
+directive @describe(text: String!) on OBJECT | ENUM_VALUE
+
+type Query @describe(text: "The root") {
+	hello: String @upper
+	shout: String @upper(exclaim: true)
+	old: String @deprecated(reason: "Use hello")
+}
+enum Color {
+	RED @describe(text: "Like blood")
+}`
+
+	gen := New()
+	gen.RegisterDirective("upper", func(site *DirectiveSite) error {
+		exclaim := site.Args["exclaim"].(bool)
+		return site.Wrap(func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+			return func(p graphql.ResolveParams) (interface{}, error) {
+				result, err := next(p)
+				if s, ok := result.(string); ok {
+					result = strings.ToUpper(s)
+					if exclaim {
+						result = result.(string) + "!"
+					}
+				}
+				return result, err
+			}
+		})
+	})
+	gen.RegisterDirective("describe", func(site *DirectiveSite) error {
+		text := site.Args["text"].(string)
+		switch {
+		case site.Object != nil:
+			site.Object.Description = text
+		case site.EnumValue != nil:
+			site.EnumValue.Description = text
+		}
+		return nil
+	})
+
+	ctx, err := gen.Generate(gql)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	if errs := ctx.Errors(); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+	resolve := func(p graphql.ResolveParams) (interface{}, error) { return "hi", nil }
+	ctx.SetResolver("Query", "hello", resolve)
+	schema, err := CreateSchemaFromContext(ctx)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	// Resolvers set after schema creation are wrapped as well
+	ctx.SetResolver("Query", "shout", resolve)
+
+	r := graphql.Do(graphql.Params{Schema: schema, RequestString: `{ hello shout }`})
+	if fmt.Sprint(r.Data) != "map[hello:HI shout:HI!]" {
+		t.Errorf("Unexpected result: %v %v", r.Data, r.Errors)
+	}
+
+	printed := PrintSchema(ctx)
+	for _, expected := range []string{
+		"\"The root\"\ntype Query",
+		"old: String @deprecated(reason: \"Use hello\")",
+		"\"Like blood\"\n  RED",
+	} {
+		if !strings.Contains(printed, expected) {
+			t.Errorf("Expected printed schema to contain %q:\n%s", expected, printed)
+		}
+	}
+}
+
+func TestDirectiveValidation(t *testing.T) {
+	tests := map[string]string{
+		`type Query { a: String @auth }`:                         "Directive @auth on Query.a: Directive may not be used on FIELD_DEFINITION.",
+		`type Query @auth(rol: "x") { a: String }`:               "Directive @auth on Query: Unknown argument rol.",
+		`type Query @auth { a: String }`:                         "Directive @auth on Query: Argument role of type String! is required.",
+		`type Query @auth(role: 1) { a: String }`:                "Directive @auth on Query: Argument role: Expected a value of type String!.",
+		`type Query @auth(role: "x", level: LOW) { a: String }`:  "",
+		`type Query @auth(role: "x", level: NONE) { a: String }`: "Directive @auth on Query: Argument level: Expected a value of type Level.",
+	}
+	for gql, expected := range tests {
+		gql = `
+directive @auth(role: String!, level: Level) on OBJECT
+enum Level { LOW HIGH }
+` + gql
+		ctx, err := Generate(gql)
+		if err != nil {
+			fmt.Print(err)
+			t.FailNow()
+		}
+		var message string
+		if errs := ctx.Errors(); len(errs) > 0 {
+			message = errs[0].(*SchemaError).Message
+		}
+		if message != expected {
+			t.Errorf("Expected error %q, got %q", expected, message)
+		}
+	}
+}
+
+func TestDirectiveOnRecursiveType(t *testing.T) {
+	gql := `
+type Query {
+	user: User
+}
+type User {
+	name: String @count
+	friend: User
+	posts: [Post]
+}
+type Post {
+	author: User
+}`
+
+	calls := 0
+	gen := New().RegisterDirective("count", func(site *DirectiveSite) error {
+		return site.Wrap(func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+			return func(p graphql.ResolveParams) (interface{}, error) {
+				calls++
+				return next(p)
+			}
+		})
+	})
+	ctx, err := gen.Generate(gql)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	ctx.SetResolver("Query", "user", func(p graphql.ResolveParams) (interface{}, error) {
+		return map[string]interface{}{"name": "Ada"}, nil
+	})
+	schema, err := CreateSchemaFromContext(ctx)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	r := graphql.Do(graphql.Params{Schema: schema, RequestString: `{ user { name } }`})
+	if fmt.Sprint(r.Data) != "map[user:map[name:Ada]]" || calls != 1 {
+		t.Errorf("Unexpected result %v with %d middleware calls", r.Data, calls)
+	}
+}
+
+func TestDirectiveDefinitions(t *testing.T) {
+	gql := `
+"Caches the result"
+directive @cached(ttl: Int = 60, scope: Scope) on FIELD_DEFINITION | OBJECT
+directive @trace on FIELD | QUERY
+enum Scope { PUBLIC PRIVATE }
+type Query {
+	a: String @cached
+}`
+
+	ctx, err := Generate(gql)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	if errs := ctx.Errors(); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+	if ctx.Directive("cached") == nil || len(ctx.Directive("cached").Args) != 2 {
+		t.Fatalf("Expected directive @cached with two arguments")
+	}
+
+	printed := PrintSchema(ctx)
+	expected := "\"Caches the result\"\ndirective @cached(scope: Scope, ttl: Int = 60) on FIELD_DEFINITION | OBJECT\n\n" +
+		"directive @trace on FIELD | QUERY\n\n"
+	if !strings.HasPrefix(printed, expected) {
+		t.Errorf("Unexpected printed directives:\n%s", printed)
+	}
+
+	schema, err := CreateSchemaFromContext(ctx)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	r := graphql.Do(graphql.Params{Schema: schema, RequestString: `query @trace { a @trace @skip(if: false) }`})
+	if len(r.Errors) > 0 {
+		t.Errorf("Unexpected errors using executable directives: %v", r.Errors)
+	}
+	r = graphql.Do(graphql.Params{Schema: schema, RequestString: `{ __schema { directives { name } } }`})
+	if !strings.Contains(fmt.Sprint(r.Data), "map[name:cached] map[name:trace]") {
+		t.Errorf("Expected directives in introspection: %v", r.Data)
+	}
+
+	ctx, err = Generate(`directive @broken on NOWHERE
+type Query { a: String }`)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	if errs := ctx.Errors(); len(errs) != 1 || errs[0].Error() != "1:1: Unknown directive location NOWHERE." {
+		t.Errorf("Unexpected errors: %v", errs)
+	}
+}
@@ -0,0 +1,463 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// DocsFormat is the format of the pages generated by Docs.
+type DocsFormat int
+
+const (
+	DocsMarkdown DocsFormat = iota
+	DocsHTML
+)
+
+// DocsConfig configures the documentation generated by Docs.
+type DocsConfig struct {
+	Format DocsFormat
+
+	// Title is the title of the index page. It defaults to API Reference.
+	Title string
+}
+
+// Docs generates the documentation of the types of the context as static pages by
+// file name: an index page and one page per type. The pages of objects and
+// interfaces list their fields with arguments, defaults and deprecations, and every
+// page lists the implementations, members or values of its type and the fields,
+// arguments, input fields and unions using the type. All types are linked to their
+// pages.
+func Docs(ctx *Context, config DocsConfig) (map[string][]byte, error) {
+	if config.Title == "" {
+		config.Title = "API Reference"
+	}
+	ext := ".md"
+	if config.Format == DocsHTML {
+		ext = ".html"
+	}
+
+	pages := buildDocPages(ctx, ext)
+	files := make(map[string][]byte, len(pages)+1)
+	for _, page := range pages {
+		var out []byte
+		var err error
+		if config.Format == DocsHTML {
+			out, err = renderHTML(docTypeTemplate, config.Title, page)
+		} else {
+			out = renderTypeMarkdown(page)
+		}
+		if err != nil {
+			return nil, err
+		}
+		files[page.Name+ext] = out
+	}
+
+	index := docIndex{Title: config.Title}
+	for _, group := range docKinds {
+		var refs []docRef
+		for _, page := range pages {
+			if page.Kind == group.kind {
+				refs = append(refs, docRef{Text: page.Name, Page: page.Name + ext, Summary: summary(page.Description)})
+			}
+		}
+		if len(refs) > 0 {
+			index.Groups = append(index.Groups, docGroup{Title: group.title, Types: refs})
+		}
+	}
+	var out []byte
+	var err error
+	if config.Format == DocsHTML {
+		out, err = renderHTML(docIndexTemplate, config.Title, index)
+	} else {
+		out = renderIndexMarkdown(index)
+	}
+	if err != nil {
+		return nil, err
+	}
+	files["index"+ext] = out
+	return files, nil
+}
+
+// docKinds are the kinds of types in the order of the index.
+var docKinds = []struct{ kind, title string }{
+	{"Object", "Objects"},
+	{"Interface", "Interfaces"},
+	{"Union", "Unions"},
+	{"Enum", "Enums"},
+	{"Input object", "Input objects"},
+	{"Scalar", "Scalars"},
+}
+
+type docIndex struct {
+	Title  string
+	Groups []docGroup
+}
+
+type docGroup struct {
+	Title string
+	Types []docRef
+}
+
+// docRef is a link to a type or field. Page is empty for built-in scalars.
+type docRef struct {
+	Text, Page, Anchor string
+
+	// Summary is the first line of the description of the target in the index.
+	Summary string
+}
+
+func (r docRef) Href() string {
+	if r.Anchor == "" {
+		return r.Page
+	}
+	return r.Page + "#" + r.Anchor
+}
+
+// docType is a type reference like [User!]! split into the link to the named type
+// and the wrapping around it.
+type docType struct {
+	Prefix string
+	Named  docRef
+	Suffix string
+}
+
+// docField is a field, argument or enum value. Its Anchor is the exact name, as
+// names differing only in case are distinct.
+type docField struct {
+	Name        string
+	Anchor      string
+	Type        docType
+	Default     string
+	Description string
+	Deprecation string
+	Args        []docField
+}
+
+type docPage struct {
+	Name        string
+	Kind        string
+	Description string
+	Implements  []docRef
+	Fields      []docField
+	Values      []docField
+
+	// Related are the implementations of interfaces and the members of unions.
+	RelatedTitle string
+	Related      []docRef
+	UsedBy       []docRef
+}
+
+func buildDocPages(ctx *Context, ext string) []*docPage {
+	typeRef := func(typ graphql.Type) docType {
+		var ref docType
+		for {
+			switch t := typ.(type) {
+			case *graphql.NonNull:
+				ref.Suffix = "!" + ref.Suffix
+				typ = t.OfType
+				continue
+			case *graphql.List:
+				ref.Prefix += "["
+				ref.Suffix = "]" + ref.Suffix
+				typ = t.OfType
+				continue
+			}
+			break
+		}
+		ref.Named = docRef{Text: typ.Name()}
+		if _, ok := ctx.GetObject(typ.Name()); ok {
+			ref.Named.Page = typ.Name() + ext
+		}
+		return ref
+	}
+	fieldRef := func(typeName, fieldName string) docRef {
+		return docRef{Text: typeName + "." + fieldName, Page: typeName + ext, Anchor: fieldName}
+	}
+
+	pages := make(map[string]*docPage)
+	usedBy := make(map[string][]docRef)
+	use := func(typ graphql.Type, ref docRef) {
+		name := namedType(typ).Name()
+		usedBy[name] = append(usedBy[name], ref)
+	}
+	addFields := func(page *docPage, fields graphql.Fields) {
+		for _, fieldName := range sortedKeys(fields) {
+			field := fields[fieldName]
+			doc := docField{
+				Name:        fieldName,
+				Anchor:      fieldName,
+				Type:        typeRef(field.Type),
+				Description: field.Description,
+				Deprecation: field.DeprecationReason,
+			}
+			use(field.Type, fieldRef(page.Name, fieldName))
+			for _, argName := range sortedKeys(field.Args) {
+				arg := field.Args[argName]
+				doc.Args = append(doc.Args, docField{
+					Name:        argName,
+					Type:        typeRef(arg.Type),
+					Default:     printDefault(arg.DefaultValue),
+					Description: arg.Description,
+				})
+				ref := fieldRef(page.Name, fieldName)
+				ref.Text = fmt.Sprintf("%s.%s(%s:)", page.Name, fieldName, argName)
+				use(arg.Type, ref)
+			}
+			page.Fields = append(page.Fields, doc)
+		}
+	}
+
+	for _, name := range ctx.TypeNames() {
+		typ, _ := ctx.GetObject(name)
+		page := &docPage{Name: name, Description: typ.Description()}
+		pages[name] = page
+		switch typ.(type) {
+		case *graphql.Scalar:
+			page.Kind = "Scalar"
+		case *graphql.Object:
+			page.Kind = "Object"
+			config := ctx.objectConfigs[name]
+			ifaces, _ := config.Interfaces.([]*graphql.Interface)
+			for _, iface := range ifaces {
+				page.Implements = append(page.Implements, typeRef(iface).Named)
+			}
+			addFields(page, configFields(config.Fields))
+		case *graphql.Interface:
+			page.Kind = "Interface"
+			addFields(page, configFields(ctx.interfaceConfigs[name].Fields))
+		case *graphql.Union:
+			page.Kind = "Union"
+			page.RelatedTitle = "Members"
+			types, _ := ctx.unionConfigs[name].Types.([]*graphql.Object)
+			for _, ob := range types {
+				page.Related = append(page.Related, typeRef(ob).Named)
+				use(ob, docRef{Text: name, Page: name + ext})
+			}
+		case *graphql.Enum:
+			page.Kind = "Enum"
+			for _, value := range ctx.enumValues(name) {
+				page.Values = append(page.Values, docField{
+					Name:        value.Name,
+					Anchor:      value.Name,
+					Description: value.Description,
+					Deprecation: value.DeprecationReason,
+				})
+			}
+		case *graphql.InputObject:
+			page.Kind = "Input object"
+			fields, _ := ctx.inputConfigs[name].Fields.(graphql.InputObjectConfigFieldMap)
+			for _, fieldName := range sortedKeys(fields) {
+				field := fields[fieldName]
+				page.Fields = append(page.Fields, docField{
+					Name:        fieldName,
+					Anchor:      fieldName,
+					Type:        typeRef(field.Type),
+					Default:     printDefault(field.DefaultValue),
+					Description: field.Description,
+				})
+				use(field.Type, fieldRef(name, fieldName))
+			}
+		}
+	}
+
+	// Implementations are only known once all objects are visited
+	for _, page := range pages {
+		for _, iface := range page.Implements {
+			impl := pages[iface.Text]
+			impl.RelatedTitle = "Implementations"
+			impl.Related = append(impl.Related, docRef{Text: page.Name, Page: page.Name + ext})
+		}
+	}
+
+	sorted := make([]*docPage, 0, len(pages))
+	for _, name := range sortedKeys(pages) {
+		page := pages[name]
+		sort.Slice(page.Related, func(i, j int) bool { return page.Related[i].Text < page.Related[j].Text })
+		page.UsedBy = usedBy[name]
+		sort.Slice(page.UsedBy, func(i, j int) bool { return page.UsedBy[i].Text < page.UsedBy[j].Text })
+		sorted = append(sorted, page)
+	}
+	return sorted
+}
+
+// summary returns the first line of a description.
+func summary(description string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(description), "\n")
+	return line
+}
+
+func markdownLink(ref docRef) string {
+	if ref.Page == "" {
+		return ref.Text
+	}
+	return fmt.Sprintf("[%s](%s)", ref.Text, ref.Href())
+}
+
+func markdownType(typ docType) string {
+	prefix := strings.ReplaceAll(typ.Prefix, "[", `\[`)
+	suffix := strings.ReplaceAll(typ.Suffix, "]", `\]`)
+	return prefix + markdownLink(typ.Named) + suffix
+}
+
+func renderIndexMarkdown(index docIndex) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", index.Title)
+	for _, group := range index.Groups {
+		fmt.Fprintf(&b, "\n## %s\n\n", group.Title)
+		for _, ref := range group.Types {
+			b.WriteString("- " + markdownLink(ref))
+			if ref.Summary != "" {
+				b.WriteString(": " + ref.Summary)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return []byte(b.String())
+}
+
+func renderTypeMarkdown(page *docPage) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n%s\n", page.Name, page.Kind)
+	if page.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", page.Description)
+	}
+	if len(page.Implements) > 0 {
+		links := make([]string, len(page.Implements))
+		for i, ref := range page.Implements {
+			links[i] = markdownLink(ref)
+		}
+		fmt.Fprintf(&b, "\nImplements %s.\n", strings.Join(links, ", "))
+	}
+
+	if len(page.Fields) > 0 {
+		b.WriteString("\n## Fields\n")
+	}
+	for _, field := range page.Fields {
+		fmt.Fprintf(&b, "\n### <a id=\"%s\"></a>%s\n\nType: %s%s\n", field.Anchor, field.Name, markdownType(field.Type), field.Default)
+		if field.Deprecation != "" {
+			fmt.Fprintf(&b, "\n**Deprecated:** %s\n", field.Deprecation)
+		}
+		if field.Description != "" {
+			fmt.Fprintf(&b, "\n%s\n", field.Description)
+		}
+		if len(field.Args) > 0 {
+			b.WriteString("\nArguments:\n\n")
+			for _, arg := range field.Args {
+				fmt.Fprintf(&b, "- `%s`: %s%s", arg.Name, markdownType(arg.Type), arg.Default)
+				if arg.Description != "" {
+					b.WriteString(" — " + strings.Join(strings.Fields(arg.Description), " "))
+				}
+				b.WriteString("\n")
+			}
+		}
+	}
+
+	if len(page.Values) > 0 {
+		b.WriteString("\n## Values\n")
+	}
+	for _, value := range page.Values {
+		fmt.Fprintf(&b, "\n### <a id=\"%s\"></a>%s\n", value.Anchor, value.Name)
+		if value.Deprecation != "" {
+			fmt.Fprintf(&b, "\n**Deprecated:** %s\n", value.Deprecation)
+		}
+		if value.Description != "" {
+			fmt.Fprintf(&b, "\n%s\n", value.Description)
+		}
+	}
+
+	for _, list := range []struct {
+		title string
+		refs  []docRef
+	}{{page.RelatedTitle, page.Related}, {"Used by", page.UsedBy}} {
+		if len(list.refs) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", list.title)
+		for _, ref := range list.refs {
+			b.WriteString("- " + markdownLink(ref) + "\n")
+		}
+	}
+	return []byte(b.String())
+}
+
+func renderHTML(tmpl *template.Template, title string, data interface{}) ([]byte, error) {
+	var b bytes.Buffer
+	err := tmpl.Execute(&b, map[string]interface{}{"Title": title, "Page": data})
+	if err != nil {
+		return nil, fmt.Errorf("Docs could not be rendered: %s", err)
+	}
+	return b.Bytes(), nil
+}
+
+const docLayout = `{{define "head"}}<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.}}</title>
+<style>
+body { font-family: sans-serif; max-width: 50em; margin: 2em auto; line-height: 1.5; }
+code { background: #f4f4f4; padding: 0 .2em; }
+.deprecated { color: #a33; }
+.kind { color: #666; }
+</style>
+</head>
+<body>
+{{end -}}
+{{define "link"}}{{if .Page}}<a href="{{.Href}}">{{.Text}}</a>{{else}}{{.Text}}{{end}}{{end -}}
+{{define "typeref"}}<code>{{.Prefix}}{{template "link" .Named}}{{.Suffix}}</code>{{end -}}
+{{define "description"}}{{range paragraphs .}}<p>{{.}}</p>
+{{end}}{{end -}}`
+
+var docIndexTemplate = template.Must(template.New("index").Funcs(docFuncs).Parse(docLayout + `{{template "head" .Title}}<h1>{{.Title}}</h1>
+{{range .Page.Groups}}<h2>{{.Title}}</h2>
+<ul>
+{{range .Types}}<li>{{template "link" .}}{{if .Summary}}: {{.Summary}}{{end}}</li>
+{{end}}</ul>
+{{end}}</body>
+</html>
+`))
+
+var docTypeTemplate = template.Must(template.New("type").Funcs(docFuncs).Parse(docLayout + `{{template "head" .Page.Name}}{{with .Page}}<p><a href="index.html">Index</a></p>
+<h1>{{.Name}}</h1>
+<p class="kind">{{.Kind}}</p>
+{{template "description" .Description}}{{if .Implements}}<p>Implements {{range $i, $ref := .Implements}}{{if $i}}, {{end}}{{template "link" $ref}}{{end}}.</p>
+{{end}}{{if .Fields}}<h2>Fields</h2>
+{{range .Fields}}<h3 id="{{.Anchor}}">{{.Name}}</h3>
+<p>Type: {{template "typeref" .Type}}{{.Default}}</p>
+{{if .Deprecation}}<p class="deprecated"><strong>Deprecated:</strong> {{.Deprecation}}</p>
+{{end}}{{template "description" .Description}}{{if .Args}}<p>Arguments:</p>
+<ul>
+{{range .Args}}<li><code>{{.Name}}</code>: {{template "typeref" .Type}}{{.Default}}{{if .Description}} — {{.Description}}{{end}}</li>
+{{end}}</ul>
+{{end}}{{end}}{{end}}{{if .Values}}<h2>Values</h2>
+{{range .Values}}<h3 id="{{.Anchor}}">{{.Name}}</h3>
+{{if .Deprecation}}<p class="deprecated"><strong>Deprecated:</strong> {{.Deprecation}}</p>
+{{end}}{{template "description" .Description}}{{end}}{{end}}{{if .Related}}<h2>{{.RelatedTitle}}</h2>
+<ul>
+{{range .Related}}<li>{{template "link" .}}</li>
+{{end}}</ul>
+{{end}}{{if .UsedBy}}<h2>Used by</h2>
+<ul>
+{{range .UsedBy}}<li>{{template "link" .}}</li>
+{{end}}</ul>
+{{end}}{{end}}</body>
+</html>
+`))
+
+var docFuncs = template.FuncMap{
+	// paragraphs splits a description at blank lines.
+	"paragraphs": func(description string) []string {
+		var paragraphs []string
+		for _, paragraph := range strings.Split(strings.TrimSpace(description), "\n\n") {
+			if paragraph = strings.TrimSpace(paragraph); paragraph != "" {
+				paragraphs = append(paragraphs, paragraph)
+			}
+		}
+		return paragraphs
+	},
+}
@@ -0,0 +1,179 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDocs(t *testing.T) {
+	ctx, err := Generate(`
+type Query {
+	"Finds a user"
+	user(id: ID!, verbose: Boolean = false): User
+	search(filter: UserFilter): [Actor!]!
+	legacy: String @deprecated(reason: "Use user.")
+}
+interface Node {
+	id: ID!
+}
+"A user"
+type User implements Node {
+	id: ID!
+	role: Role
+}
+type Bot implements Node {
+	id: ID!
+}
+union Actor = User | Bot
+enum Role {
+	"Can do anything"
+	ADMIN
+	USER @deprecated
+}
+input UserFilter {
+	role: Role = USER
+}`)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+
+	files, err := Docs(ctx, DocsConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 8 {
+		t.Errorf("Expected an index and 7 type pages, got %d pages", len(files))
+	}
+
+	expected := "# Query\n" +
+		"\n" +
+		"Object\n" +
+		"\n" +
+		"## Fields\n" +
+		"\n" +
+		"### <a id=\"legacy\"></a>legacy\n" +
+		"\n" +
+		"Type: String\n" +
+		"\n" +
+		"**Deprecated:** Use user.\n" +
+		"\n" +
+		"### <a id=\"search\"></a>search\n" +
+		"\n" +
+		"Type: \\[[Actor](Actor.md)!\\]!\n" +
+		"\n" +
+		"Arguments:\n" +
+		"\n" +
+		"- `filter`: [UserFilter](UserFilter.md)\n" +
+		"\n" +
+		"### <a id=\"user\"></a>user\n" +
+		"\n" +
+		"Type: [User](User.md)\n" +
+		"\n" +
+		"Finds a user\n" +
+		"\n" +
+		"Arguments:\n" +
+		"\n" +
+		"- `id`: ID!\n" +
+		"- `verbose`: Boolean = false\n"
+	if page := string(files["Query.md"]); page != expected {
+		t.Errorf("Expected page:\n%s\ngot:\n%s", expected, page)
+	}
+
+	expected = "# Role\n" +
+		"\n" +
+		"Enum\n" +
+		"\n" +
+		"## Values\n" +
+		"\n" +
+		"### <a id=\"ADMIN\"></a>ADMIN\n" +
+		"\n" +
+		"Can do anything\n" +
+		"\n" +
+		"### <a id=\"USER\"></a>USER\n" +
+		"\n" +
+		"**Deprecated:** No longer supported\n" +
+		"\n" +
+		"## Used by\n" +
+		"\n" +
+		"- [User.role](User.md#role)\n" +
+		"- [UserFilter.role](UserFilter.md#role)\n"
+	if page := string(files["Role.md"]); page != expected {
+		t.Errorf("Expected page:\n%s\ngot:\n%s", expected, page)
+	}
+
+	for file, parts := range map[string][]string{
+		"Node.md":  {"## Implementations\n\n- [Bot](Bot.md)\n- [User](User.md)\n"},
+		"User.md":  {"A user", "Implements [Node](Node.md).", "## Used by\n\n- [Actor](Actor.md)\n- [Query.user](Query.md#user)\n"},
+		"Actor.md": {"## Members\n\n- [Bot](Bot.md)\n- [User](User.md)\n", "- [Query.search](Query.md#search)"},
+		"index.md": {"# API Reference\n\n## Objects\n\n- [Bot](Bot.md)\n- [Query](Query.md)\n- [User](User.md): A user\n"},
+	} {
+		for _, part := range parts {
+			if !strings.Contains(string(files[file]), part) {
+				t.Errorf("Expected %s to contain:\n%s\ngot:\n%s", file, part, files[file])
+			}
+		}
+	}
+
+	files, err = Docs(ctx, DocsConfig{Format: DocsHTML, Title: "<Users>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(files["index.html"]), "<!DOCTYPE html>\n") {
+		t.Errorf("Expected an HTML document, got:\n%s", files["index.html"])
+	}
+	for file, parts := range map[string][]string{
+		"index.html": {"<title>&lt;Users&gt;</title>", `<a href="User.html">User</a>: A user`},
+		"Query.html": {`<h3 id="search">search</h3>`, `<code>[<a href="Actor.html">Actor</a>!]!</code>`, "<code>Boolean</code> = false"},
+		"User.html":  {`<a href="Query.html#user">Query.user</a>`},
+	} {
+		for _, part := range parts {
+			if !strings.Contains(string(files[file]), part) {
+				t.Errorf("Expected %s to contain:\n%s\ngot:\n%s", file, part, files[file])
+			}
+		}
+	}
+}
+
+func TestDocsAnchorsAreCaseSensitive(t *testing.T) {
+	ctx, err := Generate(`
+type Query {
+	id: ID
+	ID: ID
+	level: Level
+}
+enum Level {
+	low
+	LOW
+}`)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+
+	files, err := Docs(ctx, DocsConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for file, parts := range map[string][]string{
+		"Query.md": {`### <a id="ID"></a>ID`, `### <a id="id"></a>id`},
+		"Level.md": {`### <a id="LOW"></a>LOW`, `### <a id="low"></a>low`, "- [Query.level](Query.md#level)"},
+	} {
+		for _, part := range parts {
+			if !strings.Contains(string(files[file]), part) {
+				t.Errorf("Expected %s to contain:\n%s\ngot:\n%s", file, part, files[file])
+			}
+		}
+	}
+
+	files, err = Docs(ctx, DocsConfig{Format: DocsHTML})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, part := range []string{`<h3 id="ID">ID</h3>`, `<h3 id="id">id</h3>`} {
+		if !strings.Contains(string(files["Query.html"]), part) {
+			t.Errorf("Expected Query.html to contain:\n%s\ngot:\n%s", part, files["Query.html"])
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/printer"
+)
+
+// ReferenceResolver returns the object of an entity type identified by a
+// representation, i.e. the __typename and the key fields of the object.
+type ReferenceResolver func(ctx context.Context, representation map[string]interface{}) (interface{}, error)
+
+// federationLocations are the locations of the federation directives.
+var federationLocations = map[string][]string{
+	"key":       {graphql.DirectiveLocationObject, graphql.DirectiveLocationInterface},
+	"extends":   {graphql.DirectiveLocationObject, graphql.DirectiveLocationInterface},
+	"shareable": {graphql.DirectiveLocationObject, graphql.DirectiveLocationFieldDefinition},
+	"external":  {graphql.DirectiveLocationFieldDefinition},
+	"requires":  {graphql.DirectiveLocationFieldDefinition},
+	"provides":  {graphql.DirectiveLocationFieldDefinition},
+}
+
+// Federation makes the generated schema an Apollo Federation subgraph. The
+// directives @key, @external, @requires, @provides, @shareable and @extends are
+// checked, extensions of types the SDL does not define become definitions, and the
+// _Any scalar, the _Service type, the _Entity union of the objects with @key and the
+// _service and _entities fields of Query are generated. _service returns the SDL, and
+// _entities resolves representations with the reference resolvers registered by
+// Context.RegisterReferenceResolver. Entities without one resolve to the
+// representation itself. The types generated for @connection are marked @shareable.
+func Federation() Option {
+	return func(gen *Generator) {
+		gen.federation = true
+		for name := range federationLocations {
+			gen.RegisterDirective(name, federationDirective)
+		}
+	}
+}
+
+// federationDirective checks the location and the field set of a federation directive.
+func federationDirective(site *DirectiveSite) error {
+	allowed := false
+	for _, location := range federationLocations[site.Name] {
+		allowed = allowed || location == site.Location
+	}
+	if !allowed {
+		return fmt.Errorf("Directive may not be used on %s.", site.Location)
+	}
+
+	switch site.Name {
+	case "key", "requires", "provides":
+		fields, ok := site.Args["fields"].(string)
+		if !ok {
+			return fmt.Errorf("fields must be a String.")
+		}
+		_, err := parser.Parse(parser.ParseParams{Source: "{" + fields + "}"})
+		if err != nil || fields == "" {
+			return fmt.Errorf("Invalid field set %q.", fields)
+		}
+	}
+	return nil
+}
+
+// RegisterReferenceResolver registers the reference resolver of an entity type.
+func (g *Context) RegisterReferenceResolver(typeName string, resolve ReferenceResolver) error {
+	if !g.entities[typeName] {
+		return fmt.Errorf("Object %s is not an entity.", typeName)
+	}
+	g.referenceResolvers[typeName] = resolve
+	return nil
+}
+
+// Entities returns the names of the entity types, i.e. the objects with @key.
+func (g *Context) Entities() []string {
+	return sortedKeys(g.entities)
+}
+
+// expandFederation adds the federation types and fields to astDoc and returns the SDL
+// of the subgraph and its entity types.
+func expandFederation(astDoc *ast.Document) (string, map[string]bool) {
+	sdl, _ := printer.Print(astDoc).(string)
+
+	defined := make(map[string]bool)
+	for _, def := range astDoc.Definitions {
+		if _, ok := def.(*ast.TypeExtensionDefinition); !ok {
+			defined[definitionName(def)] = true
+		}
+	}
+
+	entities := make(map[string]bool)
+	var query *ast.ObjectDefinition
+	for i, def := range astDoc.Definitions {
+		var obdef *ast.ObjectDefinition
+		switch def.(type) {
+		case *ast.ObjectDefinition:
+			obdef = def.(*ast.ObjectDefinition)
+		case *ast.TypeExtensionDefinition:
+			obdef = def.(*ast.TypeExtensionDefinition).Definition
+			// Types owned by other subgraphs are only extended in this one
+			if !defined[obdef.Name.Value] {
+				defined[obdef.Name.Value] = true
+				astDoc.Definitions[i] = obdef
+			}
+		default:
+			continue
+		}
+		if hasDirective(obdef.Directives, "key") {
+			entities[obdef.Name.Value] = true
+		}
+		if obdef.Name.Value == "Query" && astDoc.Definitions[i] == obdef {
+			query = obdef
+		}
+	}
+
+	if query == nil {
+		query = ast.NewObjectDefinition(&ast.ObjectDefinition{
+			Name: ast.NewName(&ast.Name{Value: "Query"}),
+		})
+		astDoc.Definitions = append(astDoc.Definitions, query)
+	}
+	query.Fields = append(query.Fields, sdlField("_service", astNonNull(astNamed("_Service"))))
+	astDoc.Definitions = append(astDoc.Definitions,
+		ast.NewScalarDefinition(&ast.ScalarDefinition{
+			Name: ast.NewName(&ast.Name{Value: "_Any"}),
+		}),
+		ast.NewObjectDefinition(&ast.ObjectDefinition{
+			Name:   ast.NewName(&ast.Name{Value: "_Service"}),
+			Fields: []*ast.FieldDefinition{sdlField("sdl", astNamed("String"))},
+		}),
+	)
+
+	// A union needs members, so _Entity and _entities only exist with entities
+	if len(entities) == 0 {
+		return sdl, entities
+	}
+	union := ast.NewUnionDefinition(&ast.UnionDefinition{
+		Name: ast.NewName(&ast.Name{Value: "_Entity"}),
+	})
+	for _, name := range sortedKeys(entities) {
+		union.Types = append(union.Types, astNamed(name))
+	}
+	astDoc.Definitions = append(astDoc.Definitions, union)
+
+	entitiesField := sdlField("_entities", astNonNull(ast.NewList(&ast.List{Type: astNamed("_Entity")})))
+	entitiesField.Arguments = []*ast.InputValueDefinition{ast.NewInputValueDefinition(&ast.InputValueDefinition{
+		Name: ast.NewName(&ast.Name{Value: "representations"}),
+		Type: astNonNull(ast.NewList(&ast.List{Type: astNonNull(astNamed("_Any"))})),
+	})}
+	query.Fields = append(query.Fields, entitiesField)
+	return sdl, entities
+}
+
+// anyScalar makes config serialize and parse representations as they are.
+func anyScalar(config *graphql.ScalarConfig) {
+	identity := func(value interface{}) interface{} {
+		return value
+	}
+	config.Serialize = identity
+	config.ParseValue = identity
+	config.ParseLiteral = func(valueAST ast.Value) interface{} {
+		return astValue(valueAST)
+	}
+}
+
+// bindFederation sets the resolvers of _service, _Service.sdl and _entities and the
+// ResolveType function of _Entity and registers the middlewares of the entity types.
+func (g *Context) bindFederation() {
+	// The configs are set instead of using SetResolver, see bindNodes
+	if service, ok := g.objectConfigs["_Service"]; ok {
+		if sdl, ok := configFields(service.Fields)["sdl"]; ok {
+			sdl.Resolve = func(p graphql.ResolveParams) (interface{}, error) {
+				return g.sdl, nil
+			}
+		}
+	}
+	query, ok := g.objectConfigs["Query"]
+	if !ok {
+		return
+	}
+	fields := configFields(query.Fields)
+	if service, ok := fields["_service"]; ok {
+		service.Resolve = func(p graphql.ResolveParams) (interface{}, error) {
+			return struct{}{}, nil
+		}
+	}
+	if entities, ok := fields["_entities"]; ok {
+		entities.Resolve = func(p graphql.ResolveParams) (interface{}, error) {
+			representations, _ := p.Args["representations"].([]interface{})
+			resolved := make([]interface{}, len(representations))
+			for i, representation := range representations {
+				entity, err := g.resolveReference(p.Context, representation)
+				if err != nil {
+					return nil, err
+				}
+				resolved[i] = entity
+			}
+			return resolved, nil
+		}
+	}
+
+	if _, ok := g.unions["_Entity"]; ok {
+		g.SetResolveType("_Entity", g.resolveTypedValue(nil))
+	}
+	for _, typeName := range sortedKeys(g.entities) {
+		g.middlewares[typeName] = append(g.middlewares[typeName], unwrapTyped)
+	}
+}
+
+func (g *Context) resolveReference(ctx context.Context, representation interface{}) (interface{}, error) {
+	fields, ok := representation.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Invalid representation %v.", representation)
+	}
+	typeName, _ := fields["__typename"].(string)
+	if !g.entities[typeName] {
+		return nil, fmt.Errorf("Unknown entity type %q.", typeName)
+	}
+
+	var value interface{} = fields
+	if resolve, ok := g.referenceResolvers[typeName]; ok {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		var err error
+		if value, err = resolve(ctx, fields); err != nil {
+			return nil, err
+		}
+	}
+	if value == nil {
+		return nil, nil
+	}
+	return typedValue{typeName: typeName, value: value}, nil
+}
@@ -0,0 +1,169 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func federatedSchema(t *testing.T, gql string, bind func(ctx *Context)) graphql.Schema {
+	ctx, err := New(Federation()).Generate(gql)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	if errs := ctx.Errors(); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+	bind(ctx)
+	schema, err := CreateSchemaFromContext(ctx)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	return schema
+}
+
+func TestFederation(t *testing.T) {
+	accounts := federatedSchema(t, `
+type Query {
+	me: User
+}
+type User @key(fields: "id") {
+	id: ID!
+	username: String
+}`, func(ctx *Context) {
+		ctx.SetResolver("Query", "me", func(p graphql.ResolveParams) (interface{}, error) {
+			return map[string]interface{}{"id": "1", "username": "ada"}, nil
+		})
+	})
+
+	reviews := federatedSchema(t, `
+type Review {
+	body: String
+	author: User @provides(fields: "username")
+}
+extend type User @key(fields: "id") {
+	id: ID! @external
+	username: String @external
+	reviews: [Review]
+}`, func(ctx *Context) {
+		if err := ctx.RegisterReferenceResolver("Review", nil); err == nil {
+			t.Errorf("Expected error registering a reference resolver of a type without @key")
+		}
+		ctx.RegisterReferenceResolver("User", func(ctx context.Context, representation map[string]interface{}) (interface{}, error) {
+			id := representation["id"]
+			return map[string]interface{}{
+				"id":      id,
+				"reviews": []interface{}{map[string]interface{}{"body": fmt.Sprintf("Review of %v", id)}},
+			}, nil
+		})
+	})
+
+	// The SDL of the subgraph is served for the composition of the gateway
+	result := graphql.Do(graphql.Params{Schema: reviews, RequestString: `{ _service { sdl } }`})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	sdl := result.Data.(map[string]interface{})["_service"].(map[string]interface{})["sdl"].(string)
+	if !strings.Contains(sdl, `extend type User @key(fields: "id")`) || strings.Contains(sdl, "_entities") {
+		t.Errorf("Unexpected SDL:\n%s", sdl)
+	}
+
+	// A stand-in for the gateway fetches the user from accounts and their reviews
+	// from reviews
+	result = graphql.Do(graphql.Params{Schema: accounts, RequestString: `{ me { __typename id username } }`})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	me := result.Data.(map[string]interface{})["me"].(map[string]interface{})
+	result = graphql.Do(graphql.Params{
+		Schema: reviews,
+		RequestString: `query($representations: [_Any!]!) {
+	_entities(representations: $representations) { ... on User { reviews { body } } }
+}`,
+		VariableValues: map[string]interface{}{
+			"representations": []interface{}{map[string]interface{}{"__typename": me["__typename"], "id": me["id"]}},
+		},
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	entity := result.Data.(map[string]interface{})["_entities"].([]interface{})[0].(map[string]interface{})
+	me["reviews"] = entity["reviews"]
+	merged, _ := json.Marshal(me)
+	expected := `{"__typename":"User","id":"1","reviews":[{"body":"Review of 1"}],"username":"ada"}`
+	if string(merged) != expected {
+		t.Errorf("Expected %s, got %s", expected, merged)
+	}
+
+	// Entities without reference resolver resolve to their representation
+	result = graphql.Do(graphql.Params{
+		Schema:        accounts,
+		RequestString: `{ _entities(representations: [{__typename: "User", id: "2", username: "grace"}]) { ... on User { id username } } }`,
+	})
+	data, _ := json.Marshal(result.Data)
+	if expected := `{"_entities":[{"id":"2","username":"grace"}]}`; string(data) != expected || len(result.Errors) > 0 {
+		t.Errorf("Expected %s, got %s %v", expected, data, result.Errors)
+	}
+
+	result = graphql.Do(graphql.Params{
+		Schema:        accounts,
+		RequestString: `{ _entities(representations: [{__typename: "Review"}]) { __typename } }`,
+	})
+	if len(result.Errors) != 1 || result.Errors[0].Message != `Unknown entity type "Review".` {
+		t.Errorf("Expected an unknown entity error, got %v", result.Errors)
+	}
+}
+
+func TestFederationDirectiveValidation(t *testing.T) {
+	for gql, expected := range map[string]string{
+		`type Query { a: String @key(fields: "a") }`: "Directive @key on Query.a: Directive may not be used on FIELD_DEFINITION.",
+		`type User @key(fields: "{") { id: ID }`:     `Directive @key on User: Invalid field set "{".`,
+		`type User @key(fields: 1) { id: ID }`:       "Directive @key on User: fields must be a String.",
+		`type User { id: ID @requires(fields: "") }`: `Directive @requires on User.id: Invalid field set "".`,
+		`type User @external { id: ID }`:             "Directive @external on User: Directive may not be used on OBJECT.",
+	} {
+		ctx, err := New(Federation()).Generate(gql)
+		if err != nil {
+			fmt.Print(err)
+			t.FailNow()
+		}
+		message := ""
+		if errs := ctx.Errors(); len(errs) > 0 {
+			message = errs[0].(*SchemaError).Message
+		}
+		if message != expected {
+			t.Errorf("Expected error %q for %s, got %q", expected, gql, message)
+		}
+	}
+}
+
+func TestFederationShareableConnections(t *testing.T) {
+	ctx, err := New(Federation()).Generate(`
+type Query {
+	users: [User] @connection
+}
+type User @key(fields: "id") {
+	id: ID!
+}`)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	if errs := ctx.Errors(); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+	for _, expected := range []string{"type PageInfo @shareable {", "type UserEdge @shareable {", "type UserConnection @shareable {"} {
+		if !strings.Contains(ctx.sdl, expected) {
+			t.Errorf("Expected the SDL to contain %q:\n%s", expected, ctx.sdl)
+		}
+	}
+	if strings.Contains(ctx.sdl, "type User @key(fields: \"id\") @shareable") {
+		t.Errorf("Expected only the generated types to be shareable:\n%s", ctx.sdl)
+	}
+}
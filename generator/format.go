@@ -0,0 +1,418 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/printer"
+)
+
+// FormatOption configures Format.
+type FormatOption func(*formatter)
+
+// SortDefinitions makes Format sort the definitions, fields, arguments and input
+// fields by name. The schema definition and the directive definitions come first.
+// Enum values keep their order as it defines their default values.
+func SortDefinitions() FormatOption {
+	return func(f *formatter) {
+		f.sort = true
+	}
+}
+
+// formatIndent is the indentation of one level of nesting.
+const formatIndent = "  "
+
+type formatter struct {
+	sort bool
+
+	// leading are the comments on the lines before a node, trailing the comment on
+	// the line a node ends on and inner the comments after the last child of a node.
+	leading  map[ast.Node][]string
+	trailing map[ast.Node]string
+	inner    map[ast.Node][]string
+	final    []string
+}
+
+// sourceComment is a comment in the source. ownLine is whether only whitespace
+// precedes it on its line.
+type sourceComment struct {
+	pos     int
+	text    string
+	ownLine bool
+}
+
+// Format reformats SDL canonically. Definitions are separated by a blank line,
+// nested definitions are indented by two spaces, descriptions are put on the lines
+// before the definitions they describe and multiline descriptions become block
+// strings. Comments are kept with the definition, field, argument or enum value
+// they precede or trail.
+func Format(source string, options ...FormatOption) (string, error) {
+	astDoc, err := parser.Parse(parser.ParseParams{
+		Source: source,
+		Options: parser.ParseOptions{
+			NoLocation: false,
+			NoSource:   false,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	f := &formatter{
+		leading:  make(map[ast.Node][]string),
+		trailing: make(map[ast.Node]string),
+		inner:    make(map[ast.Node][]string),
+	}
+	for _, option := range options {
+		option(f)
+	}
+	for _, c := range scanComments(source) {
+		f.attach(astDoc, source, c)
+	}
+
+	definitions := astDoc.Definitions
+	if f.sort {
+		definitions = append([]ast.Node(nil), definitions...)
+		sort.SliceStable(definitions, func(i, j int) bool {
+			return formatOrder(definitions[i]) < formatOrder(definitions[j])
+		})
+	}
+	var b strings.Builder
+	for i, def := range definitions {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		f.printDefinition(&b, def)
+	}
+	if len(f.final) > 0 {
+		if len(definitions) > 0 {
+			b.WriteString("\n")
+		}
+		f.printComments(&b, "", f.final)
+	}
+	return b.String(), nil
+}
+
+// formatOrder is the key definitions are sorted by.
+func formatOrder(def ast.Node) string {
+	switch def.(type) {
+	case *ast.SchemaDefinition:
+		return "0"
+	case *ast.DirectiveDefinition:
+		return "1" + definitionName(def)
+	}
+	return "2" + definitionName(def)
+}
+
+// scanComments returns the comments of source. The parser drops them.
+func scanComments(source string) []sourceComment {
+	var comments []sourceComment
+	lineStart := true
+	for pos := 0; pos < len(source); pos++ {
+		switch {
+		case source[pos] == '\n' || source[pos] == '\r':
+			lineStart = true
+			continue
+		case source[pos] == ' ' || source[pos] == '\t' || source[pos] == ',':
+			continue
+		case strings.HasPrefix(source[pos:], `"""`):
+			pos += 3
+			for pos < len(source) && !strings.HasPrefix(source[pos:], `"""`) {
+				if strings.HasPrefix(source[pos:], `\"""`) {
+					pos += 3
+				}
+				pos++
+			}
+			pos += 2
+		case source[pos] == '"':
+			for pos++; pos < len(source) && source[pos] != '"' && source[pos] != '\n'; pos++ {
+				if source[pos] == '\\' {
+					pos++
+				}
+			}
+		case source[pos] == '#':
+			end := strings.IndexAny(source[pos:], "\r\n")
+			if end < 0 {
+				end = len(source) - pos
+			}
+			comments = append(comments, sourceComment{pos, strings.TrimRight(source[pos:pos+end], " \t"), lineStart})
+			pos += end - 1
+		}
+		lineStart = false
+	}
+	return comments
+}
+
+// formatChildren returns the nodes nested in a node which comments are attached to.
+func formatChildren(node ast.Node) []ast.Node {
+	var nodes []ast.Node
+	switch node := node.(type) {
+	case *ast.Document:
+		return node.Definitions
+	case *ast.SchemaDefinition:
+		for _, operation := range node.OperationTypes {
+			nodes = append(nodes, operation)
+		}
+	case *ast.ObjectDefinition:
+		for _, field := range node.Fields {
+			nodes = append(nodes, field)
+		}
+	case *ast.TypeExtensionDefinition:
+		return formatChildren(node.Definition)
+	case *ast.InterfaceDefinition:
+		for _, field := range node.Fields {
+			nodes = append(nodes, field)
+		}
+	case *ast.FieldDefinition:
+		for _, arg := range node.Arguments {
+			nodes = append(nodes, arg)
+		}
+	case *ast.DirectiveDefinition:
+		for _, arg := range node.Arguments {
+			nodes = append(nodes, arg)
+		}
+	case *ast.InputObjectDefinition:
+		for _, field := range node.Fields {
+			nodes = append(nodes, field)
+		}
+	case *ast.EnumDefinition:
+		for _, value := range node.Values {
+			nodes = append(nodes, value)
+		}
+	}
+	return nodes
+}
+
+// attach attaches a comment to the innermost node around it: to the child it
+// trails on the same line, to the following child or else to the node itself.
+func (f *formatter) attach(astDoc *ast.Document, source string, c sourceComment) {
+	var container ast.Node = astDoc
+	nodes := formatChildren(astDoc)
+	for {
+		var inside ast.Node
+		for _, node := range nodes {
+			if loc := node.GetLoc(); loc != nil && loc.Start <= c.pos && c.pos < loc.End {
+				inside = node
+			}
+		}
+		if inside == nil {
+			break
+		}
+		container, nodes = inside, formatChildren(inside)
+		if len(nodes) == 0 {
+			// Comments within a node without children, e.g. in a list of union
+			// members, are moved in front of it
+			f.leading[inside] = append(f.leading[inside], c.text)
+			return
+		}
+	}
+
+	var prev, next ast.Node
+	for _, node := range nodes {
+		if loc := node.GetLoc(); loc != nil && loc.End <= c.pos {
+			prev = node
+		} else if loc != nil && next == nil && loc.Start > c.pos {
+			next = node
+		}
+	}
+	if _, exists := f.trailing[prev]; prev != nil && !c.ownLine && !exists &&
+		!strings.ContainsAny(source[prev.GetLoc().End:c.pos], "\r\n") {
+		f.trailing[prev] = c.text
+		return
+	}
+	switch {
+	case next != nil:
+		f.leading[next] = append(f.leading[next], c.text)
+	case container == ast.Node(astDoc):
+		f.final = append(f.final, c.text)
+	default:
+		f.inner[container] = append(f.inner[container], c.text)
+	}
+}
+
+func (f *formatter) printComments(b *strings.Builder, indent string, comments []string) {
+	for _, comment := range comments {
+		b.WriteString(indent + comment + "\n")
+	}
+}
+
+// printTrailing ends the line of a node with its trailing comment.
+func (f *formatter) printTrailing(b *strings.Builder, node ast.Node) {
+	if comment, ok := f.trailing[node]; ok {
+		b.WriteString(" " + comment)
+	}
+	b.WriteString("\n")
+}
+
+func (f *formatter) printDefinition(b *strings.Builder, def ast.Node) {
+	f.printComments(b, "", f.leading[def])
+	switch def := def.(type) {
+	case *ast.SchemaDefinition:
+		b.WriteString("schema" + formatDirectives(def.Directives) + " {\n")
+		for _, operation := range def.OperationTypes {
+			f.printComments(b, formatIndent, f.leading[operation])
+			b.WriteString(formatIndent + operation.Operation + ": " + operation.Type.Name.Value)
+			f.printTrailing(b, operation)
+		}
+		f.printComments(b, formatIndent, f.inner[def])
+		b.WriteString("}")
+	case *ast.DirectiveDefinition:
+		printDescription(b, "", description(def.Description))
+		b.WriteString("directive @" + def.Name.Value + f.formatArgs(def, def.Arguments, ""))
+		locations := make([]string, len(def.Locations))
+		for i, location := range def.Locations {
+			locations[i] = location.Value
+		}
+		b.WriteString(" on " + strings.Join(locations, " | "))
+	case *ast.ScalarDefinition:
+		printDescription(b, "", description(def.Description))
+		b.WriteString("scalar " + def.Name.Value + formatDirectives(def.Directives))
+	case *ast.ObjectDefinition:
+		f.printObject(b, def, def)
+	case *ast.TypeExtensionDefinition:
+		b.WriteString("extend ")
+		f.printObject(b, def, def.Definition)
+	case *ast.InterfaceDefinition:
+		printDescription(b, "", description(def.Description))
+		b.WriteString("interface " + def.Name.Value + formatDirectives(def.Directives))
+		f.printFields(b, def, def.Fields)
+	case *ast.UnionDefinition:
+		printDescription(b, "", description(def.Description))
+		b.WriteString("union " + def.Name.Value + formatDirectives(def.Directives))
+		members := make([]string, len(def.Types))
+		for i, member := range def.Types {
+			members[i] = member.Name.Value
+		}
+		if len(members) > 0 {
+			b.WriteString(" = " + strings.Join(members, " | "))
+		}
+	case *ast.EnumDefinition:
+		printDescription(b, "", description(def.Description))
+		b.WriteString("enum " + def.Name.Value + formatDirectives(def.Directives))
+		if len(def.Values) > 0 || len(f.inner[def]) > 0 {
+			b.WriteString(" {\n")
+			for _, value := range def.Values {
+				f.printComments(b, formatIndent, f.leading[value])
+				printDescription(b, formatIndent, description(value.Description))
+				b.WriteString(formatIndent + value.Name.Value + formatDirectives(value.Directives))
+				f.printTrailing(b, value)
+			}
+			f.printComments(b, formatIndent, f.inner[def])
+			b.WriteString("}")
+		}
+	case *ast.InputObjectDefinition:
+		printDescription(b, "", description(def.Description))
+		b.WriteString("input " + def.Name.Value + formatDirectives(def.Directives))
+		if len(def.Fields) > 0 || len(f.inner[def]) > 0 {
+			b.WriteString(" {\n")
+			for _, field := range f.sortedInputValues(def.Fields) {
+				f.printInputValue(b, field, formatIndent)
+			}
+			f.printComments(b, formatIndent, f.inner[def])
+			b.WriteString("}")
+		}
+	default:
+		b.WriteString(fmt.Sprint(printer.Print(def)))
+	}
+	f.printTrailing(b, def)
+}
+
+// printObject prints an object definition or extension, node is the node comments
+// are attached to.
+func (f *formatter) printObject(b *strings.Builder, node ast.Node, def *ast.ObjectDefinition) {
+	printDescription(b, "", description(def.Description))
+	b.WriteString("type " + def.Name.Value)
+	if len(def.Interfaces) > 0 {
+		ifaces := make([]string, len(def.Interfaces))
+		for i, iface := range def.Interfaces {
+			ifaces[i] = iface.Name.Value
+		}
+		b.WriteString(" implements " + strings.Join(ifaces, " & "))
+	}
+	b.WriteString(formatDirectives(def.Directives))
+	f.printFields(b, node, def.Fields)
+}
+
+func (f *formatter) printFields(b *strings.Builder, node ast.Node, fields []*ast.FieldDefinition) {
+	if len(fields) == 0 && len(f.inner[node]) == 0 {
+		return
+	}
+	if f.sort {
+		fields = append([]*ast.FieldDefinition(nil), fields...)
+		sort.SliceStable(fields, func(i, j int) bool { return fields[i].Name.Value < fields[j].Name.Value })
+	}
+	b.WriteString(" {\n")
+	for _, field := range fields {
+		f.printComments(b, formatIndent, f.leading[field])
+		printDescription(b, formatIndent, description(field.Description))
+		fmt.Fprintf(b, "%s%s%s: %s%s", formatIndent, field.Name.Value, f.formatArgs(field, field.Arguments, formatIndent), typeString(field.Type), formatDirectives(field.Directives))
+		f.printTrailing(b, field)
+	}
+	f.printComments(b, formatIndent, f.inner[node])
+	b.WriteString("}")
+}
+
+// formatArgs formats the arguments of a field or directive on one line unless they
+// have descriptions or comments.
+func (f *formatter) formatArgs(node ast.Node, args []*ast.InputValueDefinition, indent string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	args = f.sortedInputValues(args)
+	multiline := len(f.inner[node]) > 0
+	for _, arg := range args {
+		_, trailing := f.trailing[arg]
+		multiline = multiline || arg.Description != nil || len(f.leading[arg]) > 0 || trailing
+	}
+
+	var b strings.Builder
+	if !multiline {
+		printed := make([]string, len(args))
+		for i, arg := range args {
+			printed[i] = formatInputValue(arg)
+		}
+		return "(" + strings.Join(printed, ", ") + ")"
+	}
+	b.WriteString("(\n")
+	for _, arg := range args {
+		f.printInputValue(&b, arg, indent+formatIndent)
+	}
+	f.printComments(&b, indent+formatIndent, f.inner[node])
+	b.WriteString(indent + ")")
+	return b.String()
+}
+
+func (f *formatter) sortedInputValues(values []*ast.InputValueDefinition) []*ast.InputValueDefinition {
+	if !f.sort {
+		return values
+	}
+	values = append([]*ast.InputValueDefinition(nil), values...)
+	sort.SliceStable(values, func(i, j int) bool { return values[i].Name.Value < values[j].Name.Value })
+	return values
+}
+
+func (f *formatter) printInputValue(b *strings.Builder, value *ast.InputValueDefinition, indent string) {
+	f.printComments(b, indent, f.leading[value])
+	printDescription(b, indent, description(value.Description))
+	b.WriteString(indent + formatInputValue(value))
+	f.printTrailing(b, value)
+}
+
+func formatInputValue(value *ast.InputValueDefinition) string {
+	formatted := value.Name.Value + ": " + typeString(value.Type)
+	if value.DefaultValue != nil {
+		formatted += " = " + fmt.Sprint(printer.Print(value.DefaultValue))
+	}
+	return formatted + formatDirectives(value.Directives)
+}
+
+func formatDirectives(directives []*ast.Directive) string {
+	var formatted string
+	for _, directive := range directives {
+		formatted += " " + fmt.Sprint(printer.Print(directive))
+	}
+	return formatted
+}
@@ -0,0 +1,141 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+func TestFormat(t *testing.T) {
+	source := `# The schema
+schema { query: Query }
+  type Query implements Node@cached(ttl:60){
+# Look up a user
+user(id:ID!,verbose:Boolean=false):User # by id
+	"""
+	   All users
+	"""
+  users(
+    "The filter"
+    filter: UserFilter = {name: "a"}
+  ): [User!]!
+  id: ID!
+  # nothing after this
+}
+"A node" interface Node { id: ID! }
+union Result=User|Query
+enum Role {ADMIN # all rights
+USER}
+input UserFilter{name:String,role:Role=USER}
+directive @cached(ttl: Int) on OBJECT | FIELD_DEFINITION
+extend type Query { me: User }
+type User { name: String }
+# The end`
+
+	expected := `# The schema
+schema {
+  query: Query
+}
+
+type Query implements Node @cached(ttl: 60) {
+  # Look up a user
+  user(id: ID!, verbose: Boolean = false): User # by id
+  "All users"
+  users(
+    "The filter"
+    filter: UserFilter = {name: "a"}
+  ): [User!]!
+  id: ID!
+  # nothing after this
+}
+
+"A node"
+interface Node {
+  id: ID!
+}
+
+union Result = User | Query
+
+enum Role {
+  ADMIN # all rights
+  USER
+}
+
+input UserFilter {
+  name: String
+  role: Role = USER
+}
+
+directive @cached(ttl: Int) on OBJECT | FIELD_DEFINITION
+
+extend type Query {
+  me: User
+}
+
+type User {
+  name: String
+}
+
+# The end
+`
+	formatted, err := Format(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if formatted != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, formatted)
+	}
+	if again, _ := Format(formatted); again != formatted {
+		t.Errorf("Expected formatting to be idempotent, got:\n%s", again)
+	}
+
+	sorted, err := Format(`type B { b: Int a(y: Int, x: Int): Int }
+enum E { Z A }
+directive @d on FIELD_DEFINITION
+type A { a: Int }`, SortDefinitions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = `directive @d on FIELD_DEFINITION
+
+type A {
+  a: Int
+}
+
+type B {
+  a(x: Int, y: Int): Int
+  b: Int
+}
+
+enum E {
+  Z
+  A
+}
+`
+	if sorted != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, sorted)
+	}
+
+	if _, err := Format("type {"); err == nil {
+		t.Error("Expected a syntax error")
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	for _, source := range []string{
+		"\"\"\"Says \\\"\"\" hi\nline2\"\"\"\ntype Query { a: Int }",
+		"type Query {\n  \"\"\"\n  a \\\"\"\"\n    indented \\ backslash\n  \"\"\"\n  a(\"\"\"x\n\\\"\"\"\"\"\" x: Int): Int\n}",
+		"\"quote \\\" and \\\\ backslash\" enum E { \"\"\"\\\"\"\"\"\"\" A }",
+	} {
+		formatted, err := Format(source)
+		if err != nil {
+			t.Fatalf("Expected %q to format, got %s", source, err)
+		}
+		if _, err := parser.Parse(parser.ParseParams{Source: formatted}); err != nil {
+			t.Errorf("Expected the output to parse, got %s:\n%s", err, formatted)
+		}
+		if again, err := Format(formatted); err != nil || again != formatted {
+			t.Errorf("Expected formatting to be idempotent, got %v:\n%s\nthen:\n%s", err, formatted, again)
+		}
+	}
+}
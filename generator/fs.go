@@ -0,0 +1,112 @@
+package generator
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// GenerateSources parses every source separately and generates one Context from all
+// of their definitions. Types may reference and extend types of other sources. Errors
+// name the source they originate from.
+func GenerateSources(sources ...*source.Source) (*Context, error) {
+	return New().GenerateSources(sources...)
+}
+
+// GenerateSources is like the package level GenerateSources but uses the options of gen.
+func (gen *Generator) GenerateSources(sources ...*source.Source) (*Context, error) {
+	merged := ast.NewDocument(nil)
+	defined := make(map[string]*ast.Location)
+	for _, src := range sources {
+		astDoc, err := parser.Parse(parser.ParseParams{
+			Source: src,
+			Options: parser.ParseOptions{
+				NoLocation: false,
+				NoSource:   false,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, def := range astDoc.Definitions {
+			name := definitionName(def)
+			if _, ok := def.(*ast.TypeExtensionDefinition); ok || name == "" {
+				continue
+			}
+			if first, ok := defined[name]; ok {
+				return nil, newSchemaError(def.GetLoc(), fmt.Errorf("%s is defined twice, at %s and %s.", name, locationString(first), locationString(def.GetLoc())))
+			}
+			defined[name] = def.GetLoc()
+		}
+		merged.Definitions = append(merged.Definitions, astDoc.Definitions...)
+	}
+
+	return gen.generateDocument(merged), nil
+}
+
+// locationString returns the source, line and column of loc.
+func locationString(loc *ast.Location) string {
+	sourceLoc := location.GetLocation(loc.Source, loc.Start)
+	if loc.Source.Name == defaultSourceName {
+		return fmt.Sprintf("%d:%d", sourceLoc.Line, sourceLoc.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", loc.Source.Name, sourceLoc.Line, sourceLoc.Column)
+}
+
+// GenerateFS generates one Context from the files of fsys matching any of the given
+// patterns. The patterns use the syntax of fs.Glob; every pattern has to match at
+// least one file. Each file is parsed separately and named by its path, so it works
+// with embed.FS as well as os.DirFS:
+//
+//	//go:embed schema/*.graphql
+//	var schemaFS embed.FS
+//
+//	ctx, err := generator.GenerateFS(schemaFS, "schema/*.graphql")
+func GenerateFS(fsys fs.FS, patterns ...string) (*Context, error) {
+	return New().GenerateFS(fsys, patterns...)
+}
+
+// GenerateFS is like the package level GenerateFS but uses the options of gen.
+func (gen *Generator) GenerateFS(fsys fs.FS, patterns ...string) (*Context, error) {
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("GenerateFS: No patterns given.")
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+	for _, pattern := range patterns {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("GenerateFS: Pattern %s matches no files.", pattern)
+		}
+		for _, match := range matches {
+			if !seen[match] {
+				seen[match] = true
+				files = append(files, match)
+			}
+		}
+	}
+	sort.Strings(files)
+
+	sources := make([]*source.Source, len(files))
+	for i, file := range files {
+		body, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		sources[i] = source.NewSource(&source.Source{
+			Body: body,
+			Name: file,
+		})
+	}
+
+	return gen.GenerateSources(sources...)
+}
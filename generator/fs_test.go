@@ -0,0 +1,82 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestGenerateFSResolvesAcrossFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"schema/query.graphql": {Data: []byte(`
+type Query {
+	user: User
+}`)},
+		"schema/user.graphql": {Data: []byte(`
+type User {
+	name: String
+}`)},
+		"schema/user_ext.graphql": {Data: []byte(`
+extend type User {
+	email: String
+}`)},
+	}
+
+	ctx, err := GenerateFS(fsys, "schema/*.graphql")
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	if errs := ctx.Validate(); errs != nil {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+	if _, ok := ctx.Object("User").Fields()["email"]; !ok {
+		t.Errorf("Expected extension of User from another file to be applied")
+	}
+}
+
+func TestGenerateFSErrorsCarryFileName(t *testing.T) {
+	fsys := fstest.MapFS{
+		"query.graphql": {Data: []byte(`type Query { user: User }`)},
+		"broken.graphql": {Data: []byte(`
+type User {
+	name: String
+`)},
+	}
+
+	_, err := GenerateFS(fsys, "*.graphql")
+	if err == nil || !strings.Contains(err.Error(), "broken.graphql") {
+		t.Errorf("Expected syntax error naming broken.graphql, got %v", err)
+	}
+
+	delete(fsys, "broken.graphql")
+	ctx, err := GenerateFS(fsys, "*.graphql")
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	errs := ctx.Errors()
+	if len(errs) != 1 || !strings.HasPrefix(errs[0].Error(), "query.graphql:1:1:") {
+		t.Errorf("Expected error located in query.graphql, got %v", errs)
+	}
+}
+
+func TestGenerateFSPatternWithoutMatch(t *testing.T) {
+	if _, err := GenerateFS(fstest.MapFS{}, "*.graphql"); err == nil {
+		t.Errorf("Expected error for pattern without matches")
+	}
+}
+
+func TestGenerateFSDuplicateDefinitions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.graphql": {Data: []byte("type Query { u: User }\ntype User { a: String }")},
+		"b.graphql": {Data: []byte("extend type User { c: String }\n\ntype User { b: Int }")},
+	}
+
+	_, err := GenerateFS(fsys, "*.graphql")
+	expected := "b.graphql:3:1: User is defined twice, at a.graphql:2:1 and b.graphql:3:1."
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected %q, got %v", expected, err)
+	}
+}
@@ -30,7 +30,43 @@ type Context struct {
 	scalarConfigs    map[string]graphql.ScalarConfig
 	inputConfigs     map[string]graphql.InputObjectConfig
 
+	directives       map[string]*graphql.Directive
+	directiveConfigs map[string]graphql.DirectiveConfig
+
+	generator *Generator
+	document  *ast.Document
 	processed []int
+	failures  map[int]error
+
+	// placeholders are the definitions registered with empty fields because their
+	// fields reference types which are not generated yet.
+	placeholders map[int]bool
+
+	directiveDefs map[string]*ast.DirectiveDefinition
+	middlewares   map[string][]FieldMiddleware
+	pending       map[string][]FieldMiddleware
+	constraints   map[string]*constraint
+
+	// authDirectives are the names of the directives handled by AuthDirective.
+	authDirectives map[string]bool
+
+	// use holds the middlewares of all fields, wrapped bool whether the resolvers
+	// were wrapped by CreateSchemaFromContext.
+	use     []FieldMiddleware
+	wrapped bool
+
+	nodeFetchers map[string]NodeFetcher
+
+	// sdl is the SDL of the subgraph and entities are its entity types with Federation.
+	sdl                string
+	entities           map[string]bool
+	referenceResolvers map[string]ReferenceResolver
+
+	// prefix is the prefix of the type names of the context when merged.
+	prefix string
+
+	// resolvers are the unwrapped resolve functions of the fields wrapped by middlewares.
+	resolvers map[*graphql.FieldDefinition]graphql.FieldResolveFn
 }
 
 func (g *Context) Object(which string) *graphql.Object {
@@ -57,6 +93,11 @@ func (g *Context) InputObject(which string) *graphql.InputObject {
 	return g.inputs[which]
 }
 
+// Directive returns the directive defined in the SDL with the given name.
+func (g *Context) Directive(which string) *graphql.Directive {
+	return g.directives[which]
+}
+
 func (g *Context) GetObject(which string) (graphql.Output, bool) {
 	if i, ok := g.scalars[which]; ok {
 		return i, true
@@ -215,6 +256,53 @@ func (g *Context) Extend(which string, updateFn interface{}) *Context {
 	return g
 }
 
+// SetResolver sets the resolve function of a field of an object.
+func (g *Context) SetResolver(which, fieldName string, resolve graphql.FieldResolveFn) error {
+	config, ok := g.objectConfigs[which]
+	if !ok {
+		return fmt.Errorf("Could not find Object with name %s.", which)
+	}
+	field, ok := configFields(config.Fields)[fieldName]
+	if !ok {
+		return fmt.Errorf("Object %s has no field %s.", which, fieldName)
+	}
+	field.Resolve = resolve
+
+	// The object copies the field configs once its fields are defined
+	if fieldDef, ok := g.objects[which].Fields()[fieldName]; ok {
+		fieldDef.Resolve = resolve
+		if _, wrapped := g.resolvers[fieldDef]; wrapped {
+			delete(g.resolvers, fieldDef)
+			g.wrapResolver(which, fieldName, fieldDef)
+		}
+	}
+	return nil
+}
+
+// SetResolveType sets the function resolving the object type of values of an
+// interface or union. With RelayNodes the objects fetched by node and nodes are
+// resolved before resolveType is called.
+func (g *Context) SetResolveType(which string, resolveType graphql.ResolveTypeFn) error {
+	if which == "Node" && g.generator != nil && g.generator.relayNodes {
+		resolveType = g.resolveNodeType(resolveType)
+	}
+	if iface, ok := g.interfaces[which]; ok {
+		config := g.interfaceConfigs[which]
+		config.ResolveType = resolveType
+		g.interfaceConfigs[which] = config
+		iface.ResolveType = resolveType
+		return nil
+	}
+	if union, ok := g.unions[which]; ok {
+		config := g.unionConfigs[which]
+		config.ResolveType = resolveType
+		g.unionConfigs[which] = config
+		union.ResolveType = resolveType
+		return nil
+	}
+	return fmt.Errorf("Could not find Interface or Union with name %s.", which)
+}
+
 func mapType(ctx *Context, typ ast.Type) (graphql.Output, error) {
 	switch typ.(type) {
 	case *ast.NonNull:
@@ -247,7 +335,27 @@ func mapType(ctx *Context, typ ast.Type) (graphql.Output, error) {
 			}
 		}
 	}
-	return nil, fmt.Errorf("Could not map type %s: Type not found!", typ)
+	return nil, fmt.Errorf("Could not map type %s: Type not found!", typeString(typ))
+}
+
+// description returns the text of a description, which may be missing.
+func description(value *ast.StringValue) string {
+	if value == nil {
+		return ""
+	}
+	return value.Value
+}
+
+func typeString(typ ast.Type) string {
+	switch typ.(type) {
+	case *ast.NonNull:
+		return typeString(typ.(*ast.NonNull).Type) + "!"
+	case *ast.List:
+		return "[" + typeString(typ.(*ast.List).Type) + "]"
+	case *ast.Named:
+		return typ.(*ast.Named).Name.Value
+	}
+	return ""
 }
 
 func createValues(valType interface{}) interface{} {
@@ -277,16 +385,21 @@ func mapValue(ctx *Context, value interface{}) (interface{}, error) {
 }
 
 func generateFieldArguments(ctx *Context, def *ast.FieldDefinition) (graphql.FieldConfigArgument, error) {
-	args := make(graphql.FieldConfigArgument, len(def.Arguments))
+	return generateArguments(ctx, def.Arguments)
+}
+
+func generateArguments(ctx *Context, argDefs []*ast.InputValueDefinition) (graphql.FieldConfigArgument, error) {
+	args := make(graphql.FieldConfigArgument, len(argDefs))
 
-	for _, arg := range def.Arguments {
+	for _, arg := range argDefs {
 		typ, err := mapType(ctx, arg.Type)
 		if err != nil {
 			return nil, err
 		}
 
 		argConfig := &graphql.ArgumentConfig{
-			Type: typ,
+			Type:        typ,
+			Description: description(arg.Description),
 		}
 
 		if arg.DefaultValue != nil {
@@ -315,13 +428,24 @@ func generateInputFields(ctx *Context, def *ast.InputObjectDefinition) (graphql.
 		}
 
 		field := &graphql.InputObjectFieldConfig{
-			Type: typ,
+			Type:        typ,
+			Description: description(fieldDef.Description),
 		}
 
 		if fieldDef.DefaultValue != nil {
 			field.DefaultValue = fieldDef.DefaultValue.GetValue()
 		}
 
+		err = ctx.applyDirectives(fieldDef.Directives, DirectiveSite{
+			Location:   graphql.DirectiveLocationInputFieldDefinition,
+			TypeName:   def.Name.Value,
+			FieldName:  fieldDef.Name.Value,
+			InputField: field,
+		})
+		if err != nil {
+			return nil, err
+		}
+
 		fields[fieldDef.Name.Value] = field
 	}
 
@@ -343,6 +467,7 @@ func generateFields(ctx *Context, def interface{}) (graphql.Fields, error) {
 		return nil, fmt.Errorf("GenerateFields: Given definition was no Object or Interface definition.")
 	}
 
+	typeName := definitionName(def.(ast.Node))
 	fields := make(map[string]*graphql.Field, len(fieldDefs))
 	for _, fieldDef := range fieldDefs {
 		typ, err := mapType(ctx, fieldDef.Type)
@@ -351,7 +476,8 @@ func generateFields(ctx *Context, def interface{}) (graphql.Fields, error) {
 		}
 
 		field := &graphql.Field{
-			Type: typ,
+			Type:        typ,
+			Description: description(fieldDef.Description),
 		}
 
 		args, err := generateFieldArguments(ctx, fieldDef)
@@ -362,6 +488,28 @@ func generateFields(ctx *Context, def interface{}) (graphql.Fields, error) {
 			field.Args = args
 		}
 
+		for _, argDef := range fieldDef.Arguments {
+			err := ctx.applyDirectives(argDef.Directives, DirectiveSite{
+				Location:  graphql.DirectiveLocationArgumentDefinition,
+				TypeName:  typeName,
+				FieldName: fieldDef.Name.Value,
+				ArgName:   argDef.Name.Value,
+				Argument:  args[argDef.Name.Value],
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		err = ctx.applyDirectives(fieldDef.Directives, DirectiveSite{
+			Location:  graphql.DirectiveLocationFieldDefinition,
+			TypeName:  typeName,
+			FieldName: fieldDef.Name.Value,
+			Field:     field,
+		})
+		if err != nil {
+			return nil, err
+		}
+
 		fields[fieldDef.Name.Value] = field
 	}
 
@@ -371,18 +519,36 @@ func generateFields(ctx *Context, def interface{}) (graphql.Fields, error) {
 	return nil, nil
 }
 
-func generateEnumValues(def *ast.EnumDefinition) graphql.EnumValueConfigMap {
+func generateEnumValues(ctx *Context, def *ast.EnumDefinition) (graphql.EnumValueConfigMap, error) {
 	enumMap := make(graphql.EnumValueConfigMap, len(def.Values))
 
 	for i, valueConfig := range def.Values {
-		enumMap[valueConfig.Name.Value] = &graphql.EnumValueConfig{
-			Value: i,
+		var value interface{} = i
+		if ctx.generator.stringEnums {
+			value = valueConfig.Name.Value
 		}
+		if configured, ok := ctx.generator.enumValues[def.Name.Value][valueConfig.Name.Value]; ok {
+			value = configured
+		}
+		enumValue := &graphql.EnumValueConfig{
+			Value:       value,
+			Description: description(valueConfig.Description),
+		}
+		err := ctx.applyDirectives(valueConfig.Directives, DirectiveSite{
+			Location:  graphql.DirectiveLocationEnumValue,
+			TypeName:  def.Name.Value,
+			FieldName: valueConfig.Name.Value,
+			EnumValue: enumValue,
+		})
+		if err != nil {
+			return nil, err
+		}
+		enumMap[valueConfig.Name.Value] = enumValue
 	}
 	if len(enumMap) > 0 {
-		return enumMap
+		return enumMap, nil
 	}
-	return nil
+	return nil, nil
 }
 
 func generateInterfaces(ctx *Context, obdef *ast.ObjectDefinition) ([]*graphql.Interface, error) {
@@ -417,6 +583,16 @@ func generateUnionTypes(ctx *Context, def *ast.UnionDefinition) ([]*graphql.Obje
 	return nil, nil
 }
 
+// addFields adds the fields of a definition to the fields of its placeholder. Fields
+// added by type extensions meanwhile are kept.
+func addFields(placeholderFields graphql.Fields, fields graphql.Fields) {
+	for fieldName, field := range fields {
+		if _, ok := placeholderFields[fieldName]; !ok {
+			placeholderFields[fieldName] = field
+		}
+	}
+}
+
 // Collaborate interfaces on first pass
 func walk(context *Context, astDoc *ast.Document) bool {
 	var found bool
@@ -428,20 +604,57 @@ func walk(context *Context, astDoc *ast.Document) bool {
 			}
 		}
 
+		context.pending = make(map[string][]FieldMiddleware)
 		switch def.(type) {
 		case *ast.InterfaceDefinition:
 			idef := def.(*ast.InterfaceDefinition)
 
 			iConfig := graphql.InterfaceConfig{
-				Name: idef.Name.Value,
+				Name:        idef.Name.Value,
+				Description: description(idef.Description),
 			}
 			fields, err := generateFields(context, idef)
 			if err != nil {
+				context.failures[astIndex] = err
+				if !context.placeholders[astIndex] {
+					// Register with empty fields so that types referencing it can be generated
+					iConfig.Fields = graphql.Fields{}
+					// Only the directives of the type are applied to the placeholder
+					context.pending = make(map[string][]FieldMiddleware)
+					directiveErr := context.applyDirectives(idef.Directives, DirectiveSite{
+						Location:  graphql.DirectiveLocationInterface,
+						TypeName:  idef.Name.Value,
+						Interface: &iConfig,
+					})
+					if directiveErr != nil {
+						context.failures[astIndex] = directiveErr
+						continue
+					}
+					context.interfaces[idef.Name.Value] = graphql.NewInterface(iConfig)
+					context.interfaceConfigs[idef.Name.Value] = iConfig
+					context.placeholders[astIndex] = true
+					context.commitMiddlewares()
+					found = true
+				}
 				continue // Get in next cycle
+			} else if context.placeholders[astIndex] {
+				addFields(context.interfaceConfigs[idef.Name.Value].Fields.(graphql.Fields), fields)
+				foundInCycle = true
+				break
 			} else if fields != nil {
 				iConfig.Fields = fields
 			}
 
+			err = context.applyDirectives(idef.Directives, DirectiveSite{
+				Location:  graphql.DirectiveLocationInterface,
+				TypeName:  idef.Name.Value,
+				Interface: &iConfig,
+			})
+			if err != nil {
+				context.failures[astIndex] = err
+				continue
+			}
+
 			correspondingInterface := graphql.NewInterface(iConfig)
 			context.interfaces[idef.Name.Value] = correspondingInterface
 			context.interfaceConfigs[idef.Name.Value] = iConfig
@@ -449,14 +662,29 @@ func walk(context *Context, astDoc *ast.Document) bool {
 		case *ast.EnumDefinition:
 			edef := def.(*ast.EnumDefinition)
 			eConfig := graphql.EnumConfig{
-				Name: edef.Name.Value,
+				Name:        edef.Name.Value,
+				Description: description(edef.Description),
 			}
 
-			values := generateEnumValues(edef)
+			values, err := generateEnumValues(context, edef)
+			if err != nil {
+				context.failures[astIndex] = err
+				continue
+			}
 			if values != nil {
 				eConfig.Values = values
 			}
 
+			err = context.applyDirectives(edef.Directives, DirectiveSite{
+				Location: graphql.DirectiveLocationEnum,
+				TypeName: edef.Name.Value,
+				Enum:     &eConfig,
+			})
+			if err != nil {
+				context.failures[astIndex] = err
+				continue
+			}
+
 			correspondingEnum := graphql.NewEnum(eConfig)
 			context.enums[edef.Name.Value] = correspondingEnum
 			context.enumConfigs[edef.Name.Value] = eConfig
@@ -464,7 +692,20 @@ func walk(context *Context, astDoc *ast.Document) bool {
 		case *ast.ScalarDefinition:
 			sdef := def.(*ast.ScalarDefinition)
 			sConfig := graphql.ScalarConfig{
-				Name: sdef.Name.Value,
+				Name:        sdef.Name.Value,
+				Description: description(sdef.Description),
+			}
+			if context.generator.federation && sdef.Name.Value == "_Any" {
+				anyScalar(&sConfig)
+			}
+			err := context.applyDirectives(sdef.Directives, DirectiveSite{
+				Location: graphql.DirectiveLocationScalar,
+				TypeName: sdef.Name.Value,
+				Scalar:   &sConfig,
+			})
+			if err != nil {
+				context.failures[astIndex] = err
+				continue
 			}
 			correspondingScalar := graphql.NewScalar(sConfig)
 			context.scalars[sdef.Name.Value] = correspondingScalar
@@ -473,17 +714,29 @@ func walk(context *Context, astDoc *ast.Document) bool {
 		case *ast.UnionDefinition:
 			udef := def.(*ast.UnionDefinition)
 			uConfig := graphql.UnionConfig{
-				Name: udef.Name.Value,
+				Name:        udef.Name.Value,
+				Description: description(udef.Description),
 			}
 
 			uTypes, err := generateUnionTypes(context, udef)
 			if err != nil {
+				context.failures[astIndex] = err
 				continue // Get in next cycle
 			}
 			if uTypes != nil {
 				uConfig.Types = uTypes
 			}
 
+			err = context.applyDirectives(udef.Directives, DirectiveSite{
+				Location: graphql.DirectiveLocationUnion,
+				TypeName: udef.Name.Value,
+				Union:    &uConfig,
+			})
+			if err != nil {
+				context.failures[astIndex] = err
+				continue
+			}
+
 			correspondingUnion := graphql.NewUnion(uConfig)
 			context.unions[udef.Name.Value] = correspondingUnion
 			context.unionConfigs[udef.Name.Value] = uConfig
@@ -492,10 +745,12 @@ func walk(context *Context, astDoc *ast.Document) bool {
 			obdef := def.(*ast.TypeExtensionDefinition).Definition
 			ob := context.Object(obdef.Name.Value)
 			if ob == nil {
+				context.failures[astIndex] = fmt.Errorf("Could not extend type %s: Type not found!", obdef.Name.Value)
 				continue // No object with this type. Get in next cycle.
 			}
 			fields, err := generateFields(context, obdef)
 			if err != nil {
+				context.failures[astIndex] = err
 				continue
 			} else if fields != nil {
 				for fieldName, field := range fields {
@@ -512,12 +767,14 @@ func walk(context *Context, astDoc *ast.Document) bool {
 		case *ast.ObjectDefinition:
 			obdef := def.(*ast.ObjectDefinition)
 			obConfig := graphql.ObjectConfig{
-				Name: obdef.Name.Value,
+				Name:        obdef.Name.Value,
+				Description: description(obdef.Description),
 			}
 
 			// Include interfaces
 			ifaces, err := generateInterfaces(context, obdef)
 			if err != nil {
+				context.failures[astIndex] = err
 				continue // Get i next cycle
 			}
 			if ifaces != nil {
@@ -527,28 +784,129 @@ func walk(context *Context, astDoc *ast.Document) bool {
 			// Include Fields
 			fields, err := generateFields(context, obdef)
 			if err != nil {
+				context.failures[astIndex] = err
+				if !context.placeholders[astIndex] {
+					// Register with empty fields so that types referencing it can be generated
+					obConfig.Fields = graphql.Fields{}
+					// Only the directives of the type are applied to the placeholder
+					context.pending = make(map[string][]FieldMiddleware)
+					directiveErr := context.applyDirectives(obdef.Directives, DirectiveSite{
+						Location: graphql.DirectiveLocationObject,
+						TypeName: obdef.Name.Value,
+						Object:   &obConfig,
+					})
+					if directiveErr != nil {
+						context.failures[astIndex] = directiveErr
+						continue
+					}
+					context.objects[obdef.Name.Value] = graphql.NewObject(obConfig)
+					context.objectConfigs[obdef.Name.Value] = obConfig
+					context.placeholders[astIndex] = true
+					context.commitMiddlewares()
+					found = true
+				}
 				continue // Get in next cycle
+			} else if context.placeholders[astIndex] {
+				addFields(context.objectConfigs[obdef.Name.Value].Fields.(graphql.Fields), fields)
+				foundInCycle = true
+				break
 			} else if fields != nil {
 				obConfig.Fields = fields
 			}
 
+			err = context.applyDirectives(obdef.Directives, DirectiveSite{
+				Location: graphql.DirectiveLocationObject,
+				TypeName: obdef.Name.Value,
+				Object:   &obConfig,
+			})
+			if err != nil {
+				context.failures[astIndex] = err
+				continue
+			}
+
 			correspondingObject := graphql.NewObject(obConfig)
 			context.objects[obdef.Name.Value] = correspondingObject
 			context.objectConfigs[obdef.Name.Value] = obConfig
 			foundInCycle = true
+		case *ast.DirectiveDefinition:
+			// The parser and graphql.DirectiveConfig do not support repeatable directives yet
+			ddef := def.(*ast.DirectiveDefinition)
+			dConfig := graphql.DirectiveConfig{
+				Name: ddef.Name.Value,
+			}
+			dConfig.Description = description(ddef.Description)
+			for _, loc := range ddef.Locations {
+				dConfig.Locations = append(dConfig.Locations, loc.Value)
+			}
+			if err := checkDirectiveLocations(dConfig.Locations); err != nil {
+				context.failures[astIndex] = err
+				continue
+			}
+
+			args, err := generateArguments(context, ddef.Arguments)
+			if err != nil {
+				context.failures[astIndex] = err
+				continue // Get in next cycle
+			}
+			if args != nil {
+				dConfig.Args = args
+			}
+
+			context.directives[ddef.Name.Value] = graphql.NewDirective(dConfig)
+			context.directiveConfigs[ddef.Name.Value] = dConfig
+			foundInCycle = true
 		case *ast.InputObjectDefinition:
 			idef := def.(*ast.InputObjectDefinition)
 			iConfig := graphql.InputObjectConfig{
-				Name: idef.Name.Value,
+				Name:        idef.Name.Value,
+				Description: description(idef.Description),
 			}
 
 			inputFields, err := generateInputFields(context, idef)
 			if err != nil {
+				context.failures[astIndex] = err
+				if !context.placeholders[astIndex] {
+					// Register with empty fields so that types referencing it can be generated
+					iConfig.Fields = graphql.InputObjectConfigFieldMap{}
+					// Only the directives of the type are applied to the placeholder
+					context.pending = make(map[string][]FieldMiddleware)
+					directiveErr := context.applyDirectives(idef.Directives, DirectiveSite{
+						Location:    graphql.DirectiveLocationInputObject,
+						TypeName:    idef.Name.Value,
+						InputObject: &iConfig,
+					})
+					if directiveErr != nil {
+						context.failures[astIndex] = directiveErr
+						continue
+					}
+					context.inputs[idef.Name.Value] = graphql.NewInputObject(iConfig)
+					context.inputConfigs[idef.Name.Value] = iConfig
+					context.placeholders[astIndex] = true
+					context.commitMiddlewares()
+					found = true
+				}
 				continue // Get in next cycle
+			} else if context.placeholders[astIndex] {
+				placeholderFields := context.inputConfigs[idef.Name.Value].Fields.(graphql.InputObjectConfigFieldMap)
+				for fieldName, field := range inputFields {
+					placeholderFields[fieldName] = field
+				}
+				foundInCycle = true
+				break
 			} else if inputFields != nil {
 				iConfig.Fields = inputFields
 			}
 
+			err = context.applyDirectives(idef.Directives, DirectiveSite{
+				Location:    graphql.DirectiveLocationInputObject,
+				TypeName:    idef.Name.Value,
+				InputObject: &iConfig,
+			})
+			if err != nil {
+				context.failures[astIndex] = err
+				continue
+			}
+
 			correspondingInput := graphql.NewInputObject(iConfig)
 			context.inputs[idef.Name.Value] = correspondingInput
 			context.inputConfigs[idef.Name.Value] = iConfig
@@ -556,6 +914,8 @@ func walk(context *Context, astDoc *ast.Document) bool {
 		}
 
 		if foundInCycle {
+			context.commitMiddlewares()
+			delete(context.failures, astIndex)
 			context.processed = append(context.processed, astIndex)
 			found = true
 		}
@@ -565,10 +925,15 @@ func walk(context *Context, astDoc *ast.Document) bool {
 }
 
 func Generate(source string) (*Context, error) {
+	return New().Generate(source)
+}
+
+// Generate parses source and generates a Context from its definitions.
+func (gen *Generator) Generate(source string) (*Context, error) {
 	astDoc, err := parser.Parse(parser.ParseParams{
 		Source: source,
 		Options: parser.ParseOptions{
-			NoLocation: true,
+			NoLocation: false,
 			NoSource:   false,
 		},
 	})
@@ -577,7 +942,21 @@ func Generate(source string) (*Context, error) {
 		return nil, err
 	}
 
+	return gen.generateDocument(astDoc), nil
+}
+
+func newContext() *Context {
 	context := &Context{}
+	context.failures = make(map[int]error)
+	context.placeholders = make(map[int]bool)
+	context.middlewares = make(map[string][]FieldMiddleware)
+	context.pending = make(map[string][]FieldMiddleware)
+	context.constraints = make(map[string]*constraint)
+	context.authDirectives = make(map[string]bool)
+	context.nodeFetchers = make(map[string]NodeFetcher)
+	context.entities = make(map[string]bool)
+	context.referenceResolvers = make(map[string]ReferenceResolver)
+	context.resolvers = make(map[*graphql.FieldDefinition]graphql.FieldResolveFn)
 	context.interfaces = make(map[string]*graphql.Interface)
 	context.enums = make(map[string]*graphql.Enum)
 	context.scalars = make(map[string]*graphql.Scalar)
@@ -591,9 +970,33 @@ func Generate(source string) (*Context, error) {
 	context.inputConfigs = make(map[string]graphql.InputObjectConfig)
 	context.unionConfigs = make(map[string]graphql.UnionConfig)
 	context.objectConfigs = make(map[string]graphql.ObjectConfig)
+	context.directives = make(map[string]*graphql.Directive)
+	context.directiveConfigs = make(map[string]graphql.DirectiveConfig)
+	return context
+}
+
+func (gen *Generator) generateDocument(astDoc *ast.Document) *Context {
+	context := newContext()
+	context.generator = gen
+	expandConnections(astDoc, gen.federation)
+	if gen.relayNodes {
+		expandNodes(astDoc)
+	}
+	if gen.federation {
+		context.sdl, context.entities = expandFederation(astDoc)
+	}
+	context.document = astDoc
+	context.directiveDefs = directiveDefinitions(astDoc)
 
 	for walk(context, astDoc) {
 	}
 
-	return context, nil
+	if gen.relayNodes {
+		context.bindNodes()
+	}
+	if gen.federation {
+		context.bindFederation()
+	}
+
+	return context
 }
@@ -514,3 +514,33 @@ extend type Hello {
 		printFail(expected, hello, t)
 	}
 }
+
+func TestRecursiveTypes(t *testing.T) {
+	gql := `
+type User {
+	friends: [User]
+	group: Group
+}
+type Group {
+	members: [User]
+}
+input Filter {
+	and: [Filter!]
+}`
+
+	ctx, _ := Generate(gql)
+	if errs := ctx.Errors(); errs != nil {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+	user := ctx.Object("User")
+	if user.Fields()["friends"].Type.(*graphql.List).OfType != user {
+		t.Errorf("Expected User.friends to reference User")
+	}
+	if ctx.Object("Group").Fields()["members"].Type.(*graphql.List).OfType != user {
+		t.Errorf("Expected Group.members to reference User")
+	}
+	filter := ctx.InputObject("Filter")
+	if filter.Fields()["and"].Type.(*graphql.List).OfType.(*graphql.NonNull).OfType != filter {
+		t.Errorf("Expected Filter.and to reference Filter")
+	}
+}
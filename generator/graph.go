@@ -0,0 +1,208 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// GraphFormat is the format of the diagram written by Graph.
+type GraphFormat int
+
+const (
+	GraphDOT GraphFormat = iota
+	GraphMermaid
+)
+
+// GraphConfig configures the diagram written by Graph.
+type GraphConfig struct {
+	Format GraphFormat
+
+	// Root limits the diagram to the types reachable from the named type through
+	// fields, arguments, interface implementations and union members.
+	Root string
+
+	// Depth limits the number of edges between Root and the types of the diagram.
+	// Zero means no limit.
+	Depth int
+}
+
+// graphEdgeKind is the relation of two types in the diagram.
+type graphEdgeKind int
+
+const (
+	fieldEdge graphEdgeKind = iota
+	implementsEdge
+	memberEdge
+)
+
+type graphEdge struct {
+	from, to string
+	kind     graphEdgeKind
+	label    string
+}
+
+// Graph writes the objects, interfaces, unions and input objects of the context as
+// Graphviz DOT or Mermaid diagram. Types are nodes and fields and arguments are
+// edges to the types they use. Interface implementations and union members are
+// edges styled apart from fields.
+func Graph(ctx *Context, config GraphConfig) (string, error) {
+	kinds := make(map[string]string)
+	for name := range ctx.objects {
+		kinds[name] = "object"
+	}
+	for name := range ctx.interfaces {
+		kinds[name] = "interface"
+	}
+	for name := range ctx.unions {
+		kinds[name] = "union"
+	}
+	for name := range ctx.inputs {
+		kinds[name] = "input"
+	}
+
+	var edges []graphEdge
+	use := func(from string, typ graphql.Type, label string) {
+		to := namedType(typ).Name()
+		if _, ok := kinds[to]; ok {
+			edges = append(edges, graphEdge{from, to, fieldEdge, label})
+		}
+	}
+	addFields := func(from string, fields graphql.Fields) {
+		for _, fieldName := range sortedKeys(fields) {
+			field := fields[fieldName]
+			use(from, field.Type, fieldName)
+			for _, argName := range sortedKeys(field.Args) {
+				use(from, field.Args[argName].Type, fieldName+"("+argName+")")
+			}
+		}
+	}
+	for _, name := range sortedKeys(kinds) {
+		switch kinds[name] {
+		case "object":
+			config := ctx.objectConfigs[name]
+			ifaces, _ := config.Interfaces.([]*graphql.Interface)
+			for _, iface := range ifaces {
+				edges = append(edges, graphEdge{name, iface.Name(), implementsEdge, ""})
+			}
+			addFields(name, configFields(config.Fields))
+		case "interface":
+			addFields(name, configFields(ctx.interfaceConfigs[name].Fields))
+		case "union":
+			types, _ := ctx.unionConfigs[name].Types.([]*graphql.Object)
+			for _, ob := range types {
+				edges = append(edges, graphEdge{name, ob.Name(), memberEdge, ""})
+			}
+		case "input":
+			fields, _ := ctx.inputConfigs[name].Fields.(graphql.InputObjectConfigFieldMap)
+			for _, fieldName := range sortedKeys(fields) {
+				use(name, fields[fieldName].Type, fieldName)
+			}
+		}
+	}
+
+	if config.Root != "" {
+		if _, ok := kinds[config.Root]; !ok {
+			return "", fmt.Errorf("Graph: Unknown root type %q.", config.Root)
+		}
+		reachable := reachableTypes(config.Root, config.Depth, edges)
+		for name := range kinds {
+			if !reachable[name] {
+				delete(kinds, name)
+			}
+		}
+		var kept []graphEdge
+		for _, edge := range edges {
+			if reachable[edge.from] && reachable[edge.to] {
+				kept = append(kept, edge)
+			}
+		}
+		edges = kept
+	}
+
+	if config.Format == GraphMermaid {
+		return mermaidGraph(kinds, edges), nil
+	}
+	return dotGraph(kinds, edges), nil
+}
+
+// reachableTypes returns the types reachable from root within depth edges. The
+// implementations of an interface are reachable from the interface.
+func reachableTypes(root string, depth int, edges []graphEdge) map[string]bool {
+	next := make(map[string][]string)
+	for _, edge := range edges {
+		if edge.kind == implementsEdge {
+			next[edge.to] = append(next[edge.to], edge.from)
+		}
+		next[edge.from] = append(next[edge.from], edge.to)
+	}
+
+	reachable := map[string]bool{root: true}
+	frontier := []string{root}
+	for level := 0; len(frontier) > 0 && (depth == 0 || level < depth); level++ {
+		var found []string
+		for _, name := range frontier {
+			for _, to := range next[name] {
+				if !reachable[to] {
+					reachable[to] = true
+					found = append(found, to)
+				}
+			}
+		}
+		frontier = found
+	}
+	return reachable
+}
+
+// dotNodeStyles are the node attributes of the kinds of types in DOT.
+var dotNodeStyles = map[string]string{
+	"object":    "shape=box",
+	"interface": "shape=box, style=rounded",
+	"union":     "shape=hexagon",
+	"input":     "shape=box, style=dashed",
+}
+
+func dotGraph(kinds map[string]string, edges []graphEdge) string {
+	var b strings.Builder
+	b.WriteString("digraph Schema {\n")
+	for _, name := range sortedKeys(kinds) {
+		fmt.Fprintf(&b, "\t%q [%s];\n", name, dotNodeStyles[kinds[name]])
+	}
+	for _, edge := range edges {
+		fmt.Fprintf(&b, "\t%q -> %q", edge.from, edge.to)
+		switch edge.kind {
+		case fieldEdge:
+			fmt.Fprintf(&b, " [label=%q]", edge.label)
+		case implementsEdge:
+			b.WriteString(" [style=dashed, arrowhead=empty]")
+		case memberEdge:
+			b.WriteString(" [style=dotted, arrowhead=odiamond]")
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func mermaidGraph(kinds map[string]string, edges []graphEdge) string {
+	var b strings.Builder
+	b.WriteString("classDiagram\n")
+	for _, name := range sortedKeys(kinds) {
+		fmt.Fprintf(&b, "\tclass %s\n", name)
+		if kind := kinds[name]; kind != "object" {
+			fmt.Fprintf(&b, "\t<<%s>> %s\n", kind, name)
+		}
+	}
+	for _, edge := range edges {
+		switch edge.kind {
+		case fieldEdge:
+			fmt.Fprintf(&b, "\t%s --> %s : %s\n", edge.from, edge.to, edge.label)
+		case implementsEdge:
+			fmt.Fprintf(&b, "\t%s ..|> %s\n", edge.from, edge.to)
+		case memberEdge:
+			fmt.Fprintf(&b, "\t%s o.. %s\n", edge.from, edge.to)
+		}
+	}
+	return b.String()
+}
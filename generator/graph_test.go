@@ -0,0 +1,101 @@
+package generator
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestGraph(t *testing.T) {
+	ctx, err := Generate(`
+type Query {
+	node(id: ID!): Node
+	search(filter: Filter): [Result!]!
+}
+interface Node {
+	id: ID!
+}
+type User implements Node {
+	id: ID!
+	friends: [User]
+}
+type Post implements Node {
+	id: ID!
+	author: User
+}
+union Result = User | Post
+input Filter {
+	author: AuthorFilter
+}
+input AuthorFilter {
+	name: String
+}`)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+
+	dot, err := Graph(ctx, GraphConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `digraph Schema {
+	"AuthorFilter" [shape=box, style=dashed];
+	"Filter" [shape=box, style=dashed];
+	"Node" [shape=box, style=rounded];
+	"Post" [shape=box];
+	"Query" [shape=box];
+	"Result" [shape=hexagon];
+	"User" [shape=box];
+	"Filter" -> "AuthorFilter" [label="author"];
+	"Post" -> "Node" [style=dashed, arrowhead=empty];
+	"Post" -> "User" [label="author"];
+	"Query" -> "Node" [label="node"];
+	"Query" -> "Result" [label="search"];
+	"Query" -> "Filter" [label="search(filter)"];
+	"Result" -> "User" [style=dotted, arrowhead=odiamond];
+	"Result" -> "Post" [style=dotted, arrowhead=odiamond];
+	"User" -> "Node" [style=dashed, arrowhead=empty];
+	"User" -> "User" [label="friends"];
+}
+`
+	if dot != expected {
+		t.Errorf("Expected DOT:\n%s\ngot:\n%s", expected, dot)
+	}
+
+	mermaid, err := Graph(ctx, GraphConfig{Format: GraphMermaid, Root: "Node", Depth: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = `classDiagram
+	class Node
+	<<interface>> Node
+	class Post
+	class User
+	Post ..|> Node
+	Post --> User : author
+	User ..|> Node
+	User --> User : friends
+`
+	if mermaid != expected {
+		t.Errorf("Expected Mermaid:\n%s\ngot:\n%s", expected, mermaid)
+	}
+
+	mermaid, err = Graph(ctx, GraphConfig{Format: GraphMermaid, Root: "Filter"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = `classDiagram
+	class AuthorFilter
+	<<input>> AuthorFilter
+	class Filter
+	<<input>> Filter
+	Filter --> AuthorFilter : author
+`
+	if mermaid != expected {
+		t.Errorf("Expected Mermaid:\n%s\ngot:\n%s", expected, mermaid)
+	}
+
+	if _, err := Graph(ctx, GraphConfig{Root: "Missing"}); err == nil {
+		t.Error("Expected an error for an unknown root type")
+	}
+}
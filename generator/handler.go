@@ -0,0 +1,355 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// The media types of GraphQL over HTTP responses. Clients accepting only
+// mediaTypeJSON get the legacy behavior of status 200 for all well-formed requests.
+const (
+	mediaTypeGraphQLResponse = "application/graphql-response+json"
+	mediaTypeJSON            = "application/json"
+)
+
+// Handler serves a schema over HTTP following the GraphQL over HTTP specification.
+// It executes queries sent by GET and queries and mutations sent by POST with JSON
+// bodies. A POST body with an array of requests is executed as a batch. Resolvers
+// get the context of the HTTP request.
+type Handler struct {
+	schema    func() *graphql.Schema
+	graphiQL  bool
+	rootValue func(r *http.Request) interface{}
+}
+
+// HandlerOption configures a Handler.
+type HandlerOption func(*Handler)
+
+// GraphiQL makes the handler serve a GraphiQL page to browsers requesting it
+// without a query.
+func GraphiQL() HandlerOption {
+	return func(h *Handler) {
+		h.graphiQL = true
+	}
+}
+
+// RootValue sets the function returning the root value of the operations of a
+// request.
+func RootValue(fn func(r *http.Request) interface{}) HandlerOption {
+	return func(h *Handler) {
+		h.rootValue = fn
+	}
+}
+
+// NewHandler returns a Handler serving schema, e.g. as created by
+// CreateSchemaFromContext.
+func NewHandler(schema graphql.Schema, options ...HandlerOption) *Handler {
+	return newHandler(func() *graphql.Schema { return &schema }, options)
+}
+
+// Handler returns a Handler serving the current schema of the reloader. Requests
+// fail with status 503 until a schema is loaded.
+func (r *Reloader) Handler(options ...HandlerOption) *Handler {
+	return newHandler(r.Schema, options)
+}
+
+func newHandler(schema func() *graphql.Schema, options []HandlerOption) *Handler {
+	h := &Handler{schema: schema}
+	for _, option := range options {
+		option(h)
+	}
+	return h
+}
+
+// graphQLRequest is the request of a single operation.
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+	Extensions    map[string]interface{} `json:"extensions"`
+}
+
+// requestErrors is the response to a request failing before execution, which has
+// no data entry.
+type requestErrors struct {
+	Errors []gqlerrors.FormattedError `json:"errors"`
+}
+
+func newRequestErrors(message string) requestErrors {
+	return requestErrors{[]gqlerrors.FormattedError{gqlerrors.NewFormattedError(message)}}
+}
+
+// errGetMutation marks requests of mutations sent by GET.
+var errGetMutation = errors.New("Only queries can be sent by GET.")
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	mediaType := acceptedMediaType(r.Header.Get("Accept"))
+
+	var requests []graphQLRequest
+	batch := false
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		if h.graphiQL && !query.Has("query") && acceptsHTML(r.Header.Get("Accept")) {
+			h.serveGraphiQL(w, r)
+			return
+		}
+		request := graphQLRequest{Query: query.Get("query"), OperationName: query.Get("operationName")}
+		if variables := query.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &request.Variables); err != nil {
+				h.writeError(w, mediaType, http.StatusBadRequest, "The variables must be a JSON object.")
+				return
+			}
+		}
+		if extensions := query.Get("extensions"); extensions != "" {
+			if err := json.Unmarshal([]byte(extensions), &request.Extensions); err != nil {
+				h.writeError(w, mediaType, http.StatusBadRequest, "The extensions must be a JSON object.")
+				return
+			}
+		}
+		requests = []graphQLRequest{request}
+	case http.MethodPost:
+		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if contentType != mediaTypeJSON {
+			h.writeError(w, mediaType, http.StatusUnsupportedMediaType, "The request body must be application/json.")
+			return
+		}
+		var body json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			h.writeError(w, mediaType, http.StatusBadRequest, "The request body must be valid JSON.")
+			return
+		}
+		batch = strings.HasPrefix(strings.TrimSpace(string(body)), "[")
+		var err error
+		if batch {
+			err = json.Unmarshal(body, &requests)
+		} else {
+			requests = make([]graphQLRequest, 1)
+			err = json.Unmarshal(body, &requests[0])
+		}
+		if err != nil {
+			h.writeError(w, mediaType, http.StatusBadRequest, "The request body must be a request or an array of requests.")
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		h.writeError(w, mediaType, http.StatusMethodNotAllowed, fmt.Sprintf("Method %s is not allowed.", r.Method))
+		return
+	}
+
+	if mediaType == "" {
+		h.writeError(w, mediaTypeJSON, http.StatusNotAcceptable, "The response can only be application/graphql-response+json or application/json.")
+		return
+	}
+	schema := h.schema()
+	if schema == nil {
+		h.writeError(w, mediaType, http.StatusServiceUnavailable, "The schema is not loaded.")
+		return
+	}
+
+	var rootValue interface{}
+	if h.rootValue != nil {
+		rootValue = h.rootValue(r)
+	}
+	responses := make([]interface{}, len(requests))
+	status := http.StatusOK
+	for i, request := range requests {
+		response, err := h.execute(r, schema, rootValue, request)
+		if err == errGetMutation {
+			w.Header().Set("Allow", "POST")
+			h.writeError(w, mediaType, http.StatusMethodNotAllowed, err.Error())
+			return
+		}
+		if result, ok := response.(*graphql.Result); ok && failedBeforeExecution(result) && mediaType == mediaTypeGraphQLResponse {
+			response = requestErrors{result.Errors}
+		}
+		if _, failed := response.(requestErrors); failed && mediaType == mediaTypeGraphQLResponse {
+			status = http.StatusBadRequest
+		}
+		responses[i] = response
+	}
+
+	if batch {
+		h.write(w, mediaType, http.StatusOK, responses)
+	} else {
+		h.write(w, mediaType, status, responses[0])
+	}
+}
+
+// execute executes request. It returns requestErrors if the request fails before
+// execution and errGetMutation for operations other than queries sent by GET.
+func (h *Handler) execute(r *http.Request, schema *graphql.Schema, rootValue interface{}, request graphQLRequest) (interface{}, error) {
+	if request.Query == "" {
+		return newRequestErrors("The request has no query."), nil
+	}
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(request.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return requestErrors{gqlerrors.FormatErrors(err)}, nil
+	}
+	if result := graphql.ValidateDocument(schema, doc, graphql.SpecifiedRules); !result.IsValid {
+		return requestErrors{result.Errors}, nil
+	}
+
+	operation, message := selectOperation(doc, request.OperationName)
+	if operation == nil {
+		return newRequestErrors(message), nil
+	}
+	if r.Method == http.MethodGet && operation.Operation != ast.OperationTypeQuery {
+		return nil, errGetMutation
+	}
+	if operation.Operation == ast.OperationTypeSubscription {
+		return newRequestErrors("Subscriptions are not supported over HTTP."), nil
+	}
+
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        *schema,
+		Root:          rootValue,
+		AST:           doc,
+		OperationName: request.OperationName,
+		Args:          request.Variables,
+		Context:       r.Context(),
+	}), nil
+}
+
+// failedBeforeExecution reports whether result is the failure of a request before
+// execution, e.g. on coercing the variables. Errors of fields have a path, also when
+// they null the data of the whole operation.
+func failedBeforeExecution(result *graphql.Result) bool {
+	if result.Data != nil || len(result.Errors) == 0 {
+		return false
+	}
+	for _, err := range result.Errors {
+		if len(err.Path) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// selectOperation returns the operation named name of doc, or its only operation if
+// name is empty. Otherwise it returns the error message.
+func selectOperation(doc *ast.Document, name string) (*ast.OperationDefinition, string) {
+	var operations []*ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		if operation, ok := definition.(*ast.OperationDefinition); ok {
+			operations = append(operations, operation)
+		}
+	}
+	if name == "" {
+		if len(operations) != 1 {
+			return nil, "Must provide operation name if query contains multiple operations."
+		}
+		return operations[0], ""
+	}
+	for _, operation := range operations {
+		if operation.Name != nil && operation.Name.Value == name {
+			return operation, ""
+		}
+	}
+	return nil, fmt.Sprintf("Unknown operation named %q.", name)
+}
+
+// acceptedMediaType returns the response media type for the Accept header, or an
+// empty string if no supported type is accepted. The type with the highest quality
+// wins, and types with quality 0 are not accepted. Without Accept header the legacy
+// application/json is used.
+func acceptedMediaType(accept string) string {
+	if strings.TrimSpace(accept) == "" {
+		return mediaTypeJSON
+	}
+	accepted, quality := "", 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, q := parseAccept(part)
+		if q <= quality {
+			continue
+		}
+		switch mediaType {
+		case mediaTypeGraphQLResponse, "application/*", "*/*":
+			accepted, quality = mediaTypeGraphQLResponse, q
+		case mediaTypeJSON:
+			accepted, quality = mediaTypeJSON, q
+		}
+	}
+	return accepted
+}
+
+func acceptsHTML(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		if mediaType, q := parseAccept(part); mediaType == "text/html" && q > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAccept returns the media type and quality of an entry of an Accept header.
+// Malformed entries have quality 0.
+func parseAccept(part string) (string, float64) {
+	mediaType, params, err := mime.ParseMediaType(part)
+	if err != nil {
+		return "", 0
+	}
+	q, ok := params["q"]
+	if !ok {
+		return mediaType, 1
+	}
+	quality, err := strconv.ParseFloat(q, 64)
+	if err != nil || quality < 0 || quality > 1 {
+		return mediaType, 0
+	}
+	return mediaType, quality
+}
+
+func (h *Handler) writeError(w http.ResponseWriter, mediaType string, status int, message string) {
+	if mediaType == "" {
+		mediaType = mediaTypeJSON
+	}
+	h.write(w, mediaType, status, newRequestErrors(message))
+}
+
+func (h *Handler) write(w http.ResponseWriter, mediaType string, status int, body interface{}) {
+	w.Header().Set("Content-Type", mediaType+"; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+var graphiQLTemplate = template.Must(template.New("graphiql").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>GraphiQL</title>
+<link rel="stylesheet" href="https://unpkg.com/graphiql@3/graphiql.min.css">
+<style>body { margin: 0; height: 100vh; } #graphiql { height: 100vh; }</style>
+</head>
+<body>
+<div id="graphiql"></div>
+<script src="https://unpkg.com/react@18/umd/react.production.min.js"></script>
+<script src="https://unpkg.com/react-dom@18/umd/react-dom.production.min.js"></script>
+<script src="https://unpkg.com/graphiql@3/graphiql.min.js"></script>
+<script>
+ReactDOM.createRoot(document.getElementById("graphiql")).render(
+  React.createElement(GraphiQL, {fetcher: GraphiQL.createFetcher({url: {{.}}})})
+);
+</script>
+</body>
+</html>
+`))
+
+func (h *Handler) serveGraphiQL(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	graphiQLTemplate.Execute(w, r.URL.Path)
+}
@@ -0,0 +1,277 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+type userKey struct{}
+
+func TestHandler(t *testing.T) {
+	ctx, err := Generate(`
+		type Query {
+			hello(name: String, times: Int): String
+			user: String
+			me: String!
+		}
+		type Mutation {
+			count: Int
+		}
+	`)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	ctx.SetResolver("Query", "hello", func(p graphql.ResolveParams) (interface{}, error) {
+		if name, ok := p.Args["name"].(string); ok {
+			return "hello " + name, nil
+		}
+		return "world", nil
+	})
+	ctx.SetResolver("Query", "user", func(p graphql.ResolveParams) (interface{}, error) {
+		return p.Context.Value(userKey{}), nil
+	})
+	ctx.SetResolver("Query", "me", func(p graphql.ResolveParams) (interface{}, error) {
+		return nil, fmt.Errorf("Not logged in.")
+	})
+	ctx.SetResolver("Mutation", "count", func(p graphql.ResolveParams) (interface{}, error) {
+		return 1, nil
+	})
+	schema, err := CreateSchemaFromContext(ctx)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+
+	handler := NewHandler(schema, GraphiQL())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userKey{}, "alice")))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		method      string
+		query       url.Values
+		contentType string
+		accept      string
+		body        string
+		status      int
+		mediaType   string
+		response    string
+	}{
+		{
+			name:     "get",
+			method:   "GET",
+			query:    url.Values{"query": {"query Hello($name: String) { hello(name: $name) }"}, "variables": {`{"name":"bob"}`}},
+			accept:   "application/graphql-response+json",
+			status:   http.StatusOK,
+			response: `{"data":{"hello":"hello bob"}}`,
+		},
+		{
+			name:     "get mutation",
+			method:   "GET",
+			query:    url.Values{"query": {"mutation { count }"}},
+			accept:   "application/graphql-response+json",
+			status:   http.StatusMethodNotAllowed,
+			response: `{"errors":[{"message":"Only queries can be sent by GET.","locations":[]}]}`,
+		},
+		{
+			name:        "post",
+			method:      "POST",
+			contentType: "application/json",
+			accept:      "application/graphql-response+json",
+			body:        `{"query":"query A { hello } mutation B { count }","operationName":"B"}`,
+			status:      http.StatusOK,
+			response:    `{"data":{"count":1}}`,
+		},
+		{
+			name:        "context",
+			method:      "POST",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"query":"{ user }"}`,
+			status:      http.StatusOK,
+			mediaType:   "application/json",
+			response:    `{"data":{"user":"alice"}}`,
+		},
+		{
+			name:        "batch",
+			method:      "POST",
+			contentType: "application/json",
+			accept:      "application/graphql-response+json",
+			body:        `[{"query":"{ hello }"},{"query":"{ missing }"}]`,
+			status:      http.StatusOK,
+			response:    `[{"data":{"hello":"world"}},{"errors":[{"message":"Cannot query field \"missing\" on type \"Query\".","locations":[{"line":1,"column":3}]}]}]`,
+		},
+		{
+			name:        "validation error",
+			method:      "POST",
+			contentType: "application/json",
+			accept:      "application/graphql-response+json",
+			body:        `{"query":"{ missing }"}`,
+			status:      http.StatusBadRequest,
+			response:    `{"errors":[{"message":"Cannot query field \"missing\" on type \"Query\".","locations":[{"line":1,"column":3}]}]}`,
+		},
+		{
+			name:        "legacy validation error",
+			method:      "POST",
+			contentType: "application/json",
+			accept:      "application/json",
+			body:        `{"query":"{ missing }"}`,
+			status:      http.StatusOK,
+			mediaType:   "application/json",
+			response:    `{"errors":[{"message":"Cannot query field \"missing\" on type \"Query\".","locations":[{"line":1,"column":3}]}]}`,
+		},
+		{
+			name:        "variable coercion",
+			method:      "POST",
+			contentType: "application/json",
+			accept:      "application/graphql-response+json",
+			body:        `{"query":"query($x: Int!) { hello(times: $x) }","variables":{"x":"nope"}}`,
+			status:      http.StatusBadRequest,
+			response:    `{"errors":[{"message":"Variable \"$x\" got invalid value \"nope\".\nExpected type \"Int\", found \"nope\".","locations":[{"line":1,"column":7}]}]}`,
+		},
+		{
+			name:        "legacy variable coercion",
+			method:      "POST",
+			contentType: "application/json",
+			accept:      "application/json",
+			body:        `{"query":"query($x: Int!) { hello(times: $x) }","variables":{"x":"nope"}}`,
+			status:      http.StatusOK,
+			mediaType:   "application/json",
+			response:    `{"data":null,"errors":[{"message":"Variable \"$x\" got invalid value \"nope\".\nExpected type \"Int\", found \"nope\".","locations":[{"line":1,"column":7}]}]}`,
+		},
+		{
+			name:        "non-null field error",
+			method:      "POST",
+			contentType: "application/json",
+			accept:      "application/graphql-response+json",
+			body:        `{"query":"{ me }"}`,
+			status:      http.StatusOK,
+			response:    `{"data":null,"errors":[{"message":"Not logged in.","locations":[{"line":1,"column":3}],"path":["me"]}]}`,
+		},
+		{
+			name:        "unknown operation",
+			method:      "POST",
+			contentType: "application/json",
+			accept:      "application/graphql-response+json",
+			body:        `{"query":"query A { hello }","operationName":"B"}`,
+			status:      http.StatusBadRequest,
+			response:    `{"errors":[{"message":"Unknown operation named \"B\".","locations":[]}]}`,
+		},
+		{
+			name:        "invalid json",
+			method:      "POST",
+			contentType: "application/json",
+			accept:      "application/graphql-response+json",
+			body:        `{"query":`,
+			status:      http.StatusBadRequest,
+			response:    `{"errors":[{"message":"The request body must be valid JSON.","locations":[]}]}`,
+		},
+		{
+			name:        "unsupported content type",
+			method:      "POST",
+			contentType: "text/plain",
+			accept:      "application/graphql-response+json",
+			body:        `{ hello }`,
+			status:      http.StatusUnsupportedMediaType,
+			response:    `{"errors":[{"message":"The request body must be application/json.","locations":[]}]}`,
+		},
+		{
+			name:        "accept quality",
+			method:      "POST",
+			contentType: "application/json",
+			accept:      "application/json;q=0.1, application/graphql-response+json",
+			body:        `{"query":"{ missing }"}`,
+			status:      http.StatusBadRequest,
+			response:    `{"errors":[{"message":"Cannot query field \"missing\" on type \"Query\".","locations":[{"line":1,"column":3}]}]}`,
+		},
+		{
+			name:        "unacceptable quality",
+			method:      "POST",
+			contentType: "application/json",
+			accept:      "application/graphql-response+json;q=0, application/json;q=0.5",
+			body:        `{"query":"{ missing }"}`,
+			status:      http.StatusOK,
+			mediaType:   "application/json",
+			response:    `{"errors":[{"message":"Cannot query field \"missing\" on type \"Query\".","locations":[{"line":1,"column":3}]}]}`,
+		},
+		{
+			name:      "not acceptable",
+			method:    "GET",
+			query:     url.Values{"query": {"{ hello }"}},
+			accept:    "application/xml, application/json;q=0",
+			status:    http.StatusNotAcceptable,
+			mediaType: "application/json",
+			response:  `{"errors":[{"message":"The response can only be application/graphql-response+json or application/json.","locations":[]}]}`,
+		},
+		{
+			name:     "method not allowed",
+			method:   "PUT",
+			accept:   "application/graphql-response+json",
+			status:   http.StatusMethodNotAllowed,
+			response: `{"errors":[{"message":"Method PUT is not allowed.","locations":[]}]}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request, _ := http.NewRequest(test.method, server.URL+"/graphql?"+test.query.Encode(), strings.NewReader(test.body))
+			if test.contentType != "" {
+				request.Header.Set("Content-Type", test.contentType)
+			}
+			if test.accept != "" {
+				request.Header.Set("Accept", test.accept)
+			}
+			response, err := http.DefaultClient.Do(request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer response.Body.Close()
+			body := new(strings.Builder)
+			response.Write(body)
+
+			mediaType := test.mediaType
+			if mediaType == "" {
+				mediaType = "application/graphql-response+json"
+			}
+			if response.StatusCode != test.status {
+				t.Errorf("Expected status %d, got %d", test.status, response.StatusCode)
+			}
+			if contentType := response.Header.Get("Content-Type"); contentType != mediaType+"; charset=utf-8" {
+				t.Errorf("Expected %s, got %s", mediaType, contentType)
+			}
+			if !strings.HasSuffix(body.String(), "\r\n\r\n"+test.response+"\n") {
+				t.Errorf("Expected %s, got %s", test.response, body)
+			}
+		})
+	}
+
+	request, _ := http.NewRequest("GET", server.URL+"/graphql", nil)
+	request.Header.Set("Accept", "text/html")
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	body := new(strings.Builder)
+	response.Write(body)
+	if !strings.Contains(body.String(), `GraphiQL.createFetcher({url: "/graphql"})`) {
+		t.Errorf("Expected a GraphiQL page for /graphql, got %s", body)
+	}
+}
+
+func TestReloaderHandler(t *testing.T) {
+	reloader := NewReloader(ReloaderConfig{})
+	recorder := httptest.NewRecorder()
+	reloader.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/?query=%7Bhello%7D", nil))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503 before the first reload, got %d", recorder.Code)
+	}
+}
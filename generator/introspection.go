@@ -0,0 +1,126 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// IntrospectionQuery is the query used by Introspect. It is the query GraphQL tools
+// use to fetch the complete type system of a server.
+const IntrospectionQuery = `
+query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types {
+      ...FullType
+    }
+    directives {
+      name
+      description
+      locations
+      args {
+        ...InputValue
+      }
+    }
+  }
+}
+
+fragment FullType on __Type {
+  kind
+  name
+  description
+  fields(includeDeprecated: true) {
+    name
+    description
+    args {
+      ...InputValue
+    }
+    type {
+      ...TypeRef
+    }
+    isDeprecated
+    deprecationReason
+  }
+  inputFields {
+    ...InputValue
+  }
+  interfaces {
+    ...TypeRef
+  }
+  enumValues(includeDeprecated: true) {
+    name
+    description
+    isDeprecated
+    deprecationReason
+  }
+  possibleTypes {
+    ...TypeRef
+  }
+}
+
+fragment InputValue on __InputValue {
+  name
+  description
+  type { ...TypeRef }
+  defaultValue
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType {
+    kind
+    name
+    ofType {
+      kind
+      name
+      ofType {
+        kind
+        name
+        ofType {
+          kind
+          name
+          ofType {
+            kind
+            name
+            ofType {
+              kind
+              name
+              ofType {
+                kind
+                name
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}
+`
+
+// Introspect runs the introspection query against the schema of the context and
+// returns its result.
+func Introspect(ctx *Context) (*graphql.Result, error) {
+	schema, err := CreateSchemaFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: IntrospectionQuery,
+	})
+	if len(result.Errors) > 0 {
+		messages := make([]string, len(result.Errors))
+		for i, err := range result.Errors {
+			messages[i] = err.Message
+		}
+		return nil, fmt.Errorf("Introspection failed: %s", strings.Join(messages, "; "))
+	}
+	return result, nil
+}
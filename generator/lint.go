@@ -0,0 +1,319 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// LintLevel is the level a lint rule reports its diagnostics with.
+type LintLevel int
+
+const (
+	LintOff LintLevel = iota
+	LintWarn
+	LintError
+)
+
+func (l LintLevel) String() string {
+	switch l {
+	case LintWarn:
+		return "warning"
+	case LintError:
+		return "error"
+	}
+	return "off"
+}
+
+// LintConfig sets the levels of lint rules by name. Rules which are not given keep
+// their default level.
+type LintConfig map[string]LintLevel
+
+// LintRule is a rule checked by Lint.
+type LintRule struct {
+	Name        string
+	Description string
+	Level       LintLevel
+}
+
+// LintDiagnostic is a violation of a lint rule. It points to the definition, field,
+// argument or enum value violating the rule.
+type LintDiagnostic struct {
+	Rule    string
+	Level   LintLevel
+	Message string
+	Source  string
+	Line    int
+	Column  int
+}
+
+func (d LintDiagnostic) String() string {
+	located := SchemaError{
+		Message: fmt.Sprintf("%s: %s (%s)", d.Level, d.Message, d.Rule),
+		Source:  d.Source,
+		Line:    d.Line,
+		Column:  d.Column,
+	}
+	return located.Error()
+}
+
+type lintRule struct {
+	LintRule
+	check func(l *linter, def ast.Node)
+}
+
+var lintRules = []lintRule{
+	{LintRule{"type-names", "Types are PascalCase.", LintError}, lintTypeNames},
+	{LintRule{"field-names", "Fields, arguments and input fields are camelCase.", LintError}, lintFieldNames},
+	{LintRule{"enum-values", "Enum values are SCREAMING_SNAKE_CASE.", LintError}, lintEnumValues},
+	{LintRule{"descriptions", "Types other than the operation types have a description.", LintWarn}, lintDescriptions},
+	{LintRule{"entity-ids", "Entities have a field of type ID!. Objects with @key, implementing Node or returned by a root field with an id argument are entities.", LintWarn}, lintEntityIDs},
+	{LintRule{"input-names", "Input object names end with Input.", LintWarn}, lintInputNames},
+	{LintRule{"connection-names", "Objects with edges and pageInfo fields are named *Connection and their edges *Edge.", LintWarn}, lintConnectionNames},
+}
+
+// LintRules returns the rules checked by Lint with their default levels.
+func LintRules() []LintRule {
+	rules := make([]LintRule, len(lintRules))
+	for i, rule := range lintRules {
+		rules[i] = rule.LintRule
+	}
+	return rules
+}
+
+var (
+	pascalCase         = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+	camelCase          = regexp.MustCompile(`^[a-z][A-Za-z0-9]*$`)
+	screamingSnakeCase = regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`)
+)
+
+type linter struct {
+	rule        lintRule
+	level       LintLevel
+	entities    map[string]bool
+	diagnostics []LintDiagnostic
+}
+
+// Lint checks the definitions of the SDL of the context against the lint rules
+// returned by LintRules with the levels of config. Definitions added by the
+// generator, like connection types, and names starting with an underscore are not
+// checked. The diagnostics are sorted by location.
+func Lint(ctx *Context, config LintConfig) ([]LintDiagnostic, error) {
+	known := make(map[string]bool)
+	for _, rule := range lintRules {
+		known[rule.Name] = true
+	}
+	for _, name := range sortedKeys(config) {
+		if !known[name] {
+			return nil, fmt.Errorf("Unknown lint rule %q.", name)
+		}
+	}
+	if ctx.document == nil {
+		return nil, nil
+	}
+
+	l := &linter{entities: lintEntities(ctx.document)}
+	for _, rule := range lintRules {
+		l.rule, l.level = rule, rule.Level
+		if level, ok := config[rule.Name]; ok {
+			l.level = level
+		}
+		if l.level == LintOff {
+			continue
+		}
+		for _, def := range ctx.document.Definitions {
+			if def.GetLoc() == nil || strings.HasPrefix(definitionName(def), "_") {
+				continue
+			}
+			rule.check(l, def)
+		}
+	}
+
+	sort.SliceStable(l.diagnostics, func(i, j int) bool {
+		a, b := l.diagnostics[i], l.diagnostics[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return l.diagnostics, nil
+}
+
+func (l *linter) report(node ast.Node, format string, args ...interface{}) {
+	located := newSchemaError(node.GetLoc(), fmt.Errorf(format, args...))
+	l.diagnostics = append(l.diagnostics, LintDiagnostic{
+		Rule:    l.rule.Name,
+		Level:   l.level,
+		Message: located.Message,
+		Source:  located.Source,
+		Line:    located.Line,
+		Column:  located.Column,
+	})
+}
+
+// objectFields returns the fields of object and interface definitions and extensions.
+func objectFields(def ast.Node) (string, []*ast.FieldDefinition) {
+	switch def := def.(type) {
+	case *ast.ObjectDefinition:
+		return def.Name.Value, def.Fields
+	case *ast.InterfaceDefinition:
+		return def.Name.Value, def.Fields
+	case *ast.TypeExtensionDefinition:
+		return def.Definition.Name.Value, def.Definition.Fields
+	}
+	return "", nil
+}
+
+func namedTypeName(typ ast.Type) string {
+	return strings.Trim(typeString(typ), "[]!")
+}
+
+func lintTypeNames(l *linter, def ast.Node) {
+	if _, ok := def.(*ast.TypeExtensionDefinition); ok {
+		return
+	}
+	if name := definitionName(def); !strings.HasPrefix(name, "@") && !pascalCase.MatchString(name) {
+		l.report(def, "Type %s is not PascalCase.", name)
+	}
+}
+
+func lintFieldNames(l *linter, def ast.Node) {
+	if typeName, fields := objectFields(def); fields != nil {
+		for _, field := range fields {
+			if !camelCase.MatchString(field.Name.Value) {
+				l.report(field, "Field %s.%s is not camelCase.", typeName, field.Name.Value)
+			}
+			for _, arg := range field.Arguments {
+				if !camelCase.MatchString(arg.Name.Value) {
+					l.report(arg, "Argument %s.%s(%s:) is not camelCase.", typeName, field.Name.Value, arg.Name.Value)
+				}
+			}
+		}
+	}
+	if input, ok := def.(*ast.InputObjectDefinition); ok {
+		for _, field := range input.Fields {
+			if !camelCase.MatchString(field.Name.Value) {
+				l.report(field, "Input field %s.%s is not camelCase.", input.Name.Value, field.Name.Value)
+			}
+		}
+	}
+}
+
+func lintEnumValues(l *linter, def ast.Node) {
+	enum, ok := def.(*ast.EnumDefinition)
+	if !ok {
+		return
+	}
+	for _, value := range enum.Values {
+		if !screamingSnakeCase.MatchString(value.Name.Value) {
+			l.report(value, "Enum value %s.%s is not SCREAMING_SNAKE_CASE.", enum.Name.Value, value.Name.Value)
+		}
+	}
+}
+
+func lintDescriptions(l *linter, def ast.Node) {
+	var description *ast.StringValue
+	switch def := def.(type) {
+	case *ast.ObjectDefinition:
+		description = def.Description
+	case *ast.InterfaceDefinition:
+		description = def.Description
+	case *ast.UnionDefinition:
+		description = def.Description
+	case *ast.EnumDefinition:
+		description = def.Description
+	case *ast.InputObjectDefinition:
+		description = def.Description
+	case *ast.ScalarDefinition:
+		description = def.Description
+	default:
+		return
+	}
+	name := definitionName(def)
+	if !rootTypeNames[name] && (description == nil || strings.TrimSpace(description.Value) == "") {
+		l.report(def, "Type %s has no description.", name)
+	}
+}
+
+// lintEntities returns the names of the objects considered entities by the
+// entity-ids rule.
+func lintEntities(astDoc *ast.Document) map[string]bool {
+	entities := make(map[string]bool)
+	for _, def := range astDoc.Definitions {
+		typeName, fields := objectFields(def)
+		if ob, ok := def.(*ast.ObjectDefinition); ok {
+			if hasDirective(ob.Directives, "key") {
+				entities[typeName] = true
+			}
+			for _, iface := range ob.Interfaces {
+				if iface.Name.Value == "Node" {
+					entities[typeName] = true
+				}
+			}
+		}
+		if !rootTypeNames[typeName] {
+			continue
+		}
+		for _, field := range fields {
+			for _, arg := range field.Arguments {
+				if arg.Name.Value == "id" {
+					entities[namedTypeName(field.Type)] = true
+				}
+			}
+		}
+	}
+	return entities
+}
+
+func lintEntityIDs(l *linter, def ast.Node) {
+	ob, ok := def.(*ast.ObjectDefinition)
+	if !ok || !l.entities[ob.Name.Value] {
+		return
+	}
+	for _, field := range ob.Fields {
+		if typeString(field.Type) == "ID!" {
+			return
+		}
+	}
+	l.report(def, "Entity %s has no field of type ID!.", ob.Name.Value)
+}
+
+func lintInputNames(l *linter, def ast.Node) {
+	if input, ok := def.(*ast.InputObjectDefinition); ok && !strings.HasSuffix(input.Name.Value, "Input") {
+		l.report(def, "Input object %s is not named *Input.", input.Name.Value)
+	}
+}
+
+func lintConnectionNames(l *linter, def ast.Node) {
+	ob, ok := def.(*ast.ObjectDefinition)
+	if !ok {
+		return
+	}
+	var edges, pageInfo *ast.FieldDefinition
+	for _, field := range ob.Fields {
+		switch field.Name.Value {
+		case "edges":
+			edges = field
+		case "pageInfo":
+			pageInfo = field
+		}
+	}
+	if edges == nil || pageInfo == nil {
+		return
+	}
+	name := ob.Name.Value
+	if !strings.HasSuffix(name, "Connection") {
+		l.report(def, "Connection %s is not named *Connection.", name)
+		return
+	}
+	edgeName := strings.TrimSuffix(name, "Connection") + "Edge"
+	if namedTypeName(edges.Type) != edgeName {
+		l.report(edges, "The edges of %s are not of type %s.", name, edgeName)
+	}
+}
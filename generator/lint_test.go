@@ -0,0 +1,84 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	ctx, err := Generate(`type Query {
+	user(id: ID!): User
+	users(First: Int): UserList
+	search(filter: userFilter): [Node]
+}
+"A node"
+interface Node {
+	id: ID!
+}
+"A user"
+type User {
+	name: String
+	Role: Role
+}
+"A role"
+enum Role {
+	ADMIN
+	superUser
+}
+"A filter"
+input userFilter {
+	name: String
+}
+"Users"
+type UserList {
+	edges: [UserEdge]
+	pageInfo: PageInfo!
+}
+"A user edge"
+type UserEdge {
+	node: User
+	cursor: String!
+}
+"A page"
+type PageInfo {
+	hasNextPage: Boolean!
+}
+type Post implements Node {
+	id: ID!
+}`)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+
+	diagnostics, err := Lint(ctx, LintConfig{"descriptions": LintError, "connection-names": LintOff})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for _, diagnostic := range diagnostics {
+		lines = append(lines, diagnostic.String())
+	}
+	expected := []string{
+		"3:8: error: Argument Query.users(First:) is not camelCase. (field-names)",
+		"10:1: warning: Entity User has no field of type ID!. (entity-ids)",
+		"13:2: error: Field User.Role is not camelCase. (field-names)",
+		"18:2: error: Enum value Role.superUser is not SCREAMING_SNAKE_CASE. (enum-values)",
+		"20:1: error: Type userFilter is not PascalCase. (type-names)",
+		"20:1: warning: Input object userFilter is not named *Input. (input-names)",
+		"38:1: error: Type Post has no description. (descriptions)",
+	}
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected diagnostics:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(lines, "\n"))
+	}
+
+	diagnostics, _ = Lint(ctx, LintConfig{"type-names": LintOff, "field-names": LintOff, "enum-values": LintOff, "descriptions": LintOff, "entity-ids": LintOff, "input-names": LintOff})
+	if len(diagnostics) != 1 || diagnostics[0].Message != "Connection UserList is not named *Connection." {
+		t.Errorf("Expected a connection-names diagnostic, got %v", diagnostics)
+	}
+
+	if _, err := Lint(ctx, LintConfig{"missing": LintWarn}); err == nil {
+		t.Error("Expected an error for an unknown rule")
+	}
+}
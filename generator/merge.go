@@ -0,0 +1,338 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Prefix sets the prefix Merge adds to the names of the types of the context except
+// the root types, e.g. Billing to turn Invoice into BillingInvoice.
+func (g *Context) Prefix(prefix string) *Context {
+	g.prefix = prefix
+	return g
+}
+
+func (g *Context) mergedName(name string) string {
+	if rootTypeNames[name] {
+		return name
+	}
+	return g.prefix + name
+}
+
+// merger builds the types of a merged context.
+type merger struct {
+	dst *Context
+
+	// origins are the contexts the types and root fields of dst are taken from.
+	origins map[string]*Context
+}
+
+type mergeSource struct {
+	ctx        *Context
+	name, kind string
+}
+
+// Merge combines the types of contexts into a new context. The fields of the root
+// types are merged, and types of the same name are taken from the first context
+// defining them if the other contexts define them the same way. Conflicting
+// definitions of types, root fields and directives are reported. The resolvers,
+// middlewares and constraints of the contexts are kept, and the merged context is
+// used like a generated one, e.g. to create a schema. It has no SDL document.
+func Merge(ctxs ...*Context) (*Context, error) {
+	m := &merger{dst: newContext(), origins: make(map[string]*Context)}
+	var conflicts []string
+
+	// Types referenced by others are created first, fields once all types exist
+	var sources, duplicates []mergeSource
+	for _, kind := range []string{"scalar", "enum", "input", "interface", "object", "union"} {
+		for _, ctx := range ctxs {
+			for _, name := range ctx.typeNames(kind) {
+				source := mergeSource{ctx, name, kind}
+				target := ctx.mergedName(name)
+				if _, exists := m.origins[target]; exists && !rootTypeNames[target] {
+					duplicates = append(duplicates, source)
+					continue
+				}
+				if _, exists := m.origins[target]; !exists {
+					m.origins[target] = ctx
+					m.create(m.dst, source)
+				}
+				sources = append(sources, source)
+			}
+		}
+	}
+	for _, source := range sources {
+		conflicts = append(conflicts, m.fill(m.dst, source)...)
+	}
+
+	// Duplicates are built aside and compared with the merged types
+	for _, source := range duplicates {
+		target := source.ctx.mergedName(source.name)
+		aside := newContext()
+		m.create(aside, source)
+		m.fill(aside, source)
+		asideType, _ := aside.GetObject(target)
+		mergedType, _ := m.dst.GetObject(target)
+		if printType(aside, asideType) != printType(m.dst, mergedType) {
+			conflicts = append(conflicts, fmt.Sprintf("Conflicting definitions of type %s.", target))
+		}
+	}
+
+	for _, ctx := range ctxs {
+		conflicts = append(conflicts, m.mergeDirectives(ctx)...)
+		m.mergeMiddlewares(ctx)
+	}
+
+	if len(conflicts) > 0 {
+		return nil, errors.New(strings.Join(conflicts, "\n"))
+	}
+	return m.dst, nil
+}
+
+// typeNames returns the sorted names of the types of a kind.
+func (g *Context) typeNames(kind string) []string {
+	switch kind {
+	case "scalar":
+		return sortedKeys(g.scalarConfigs)
+	case "enum":
+		return sortedKeys(g.enumConfigs)
+	case "input":
+		return sortedKeys(g.inputConfigs)
+	case "interface":
+		return sortedKeys(g.interfaceConfigs)
+	case "object":
+		return sortedKeys(g.objectConfigs)
+	case "union":
+		return sortedKeys(g.unionConfigs)
+	}
+	return nil
+}
+
+// typ returns the type of dst corresponding to a type of src.
+func (m *merger) typ(src *Context, typ graphql.Type) graphql.Type {
+	switch t := typ.(type) {
+	case *graphql.NonNull:
+		return graphql.NewNonNull(m.typ(src, t.OfType))
+	case *graphql.List:
+		return graphql.NewList(m.typ(src, t.OfType))
+	}
+	if _, ok := src.GetObject(typ.Name()); !ok {
+		return typ // Built-in scalars
+	}
+	merged, _ := m.dst.GetObject(src.mergedName(typ.Name()))
+	return merged
+}
+
+// resolveType returns the object of dst corresponding to the object resolveType
+// resolves in src.
+func (m *merger) resolveType(src *Context, resolveType graphql.ResolveTypeFn) graphql.ResolveTypeFn {
+	if resolveType == nil {
+		return nil
+	}
+	return func(p graphql.ResolveTypeParams) *graphql.Object {
+		ob := resolveType(p)
+		if ob == nil {
+			return nil
+		}
+		return m.dst.objects[src.mergedName(ob.Name())]
+	}
+}
+
+// create adds the type of source to ctx without fields.
+func (m *merger) create(ctx *Context, source mergeSource) {
+	src, target := source.ctx, source.ctx.mergedName(source.name)
+	switch source.kind {
+	case "scalar":
+		config := src.scalarConfigs[source.name]
+		config.Name = target
+		ctx.scalars[target] = graphql.NewScalar(config)
+		ctx.scalarConfigs[target] = config
+	case "enum":
+		config := src.enumConfigs[source.name]
+		config.Name = target
+		ctx.enums[target] = graphql.NewEnum(config)
+		ctx.enumConfigs[target] = config
+	case "input":
+		config := src.inputConfigs[source.name]
+		config.Name = target
+		config.Fields = graphql.InputObjectConfigFieldMap{}
+		ctx.inputs[target] = graphql.NewInputObject(config)
+		ctx.inputConfigs[target] = config
+	case "interface":
+		config := src.interfaceConfigs[source.name]
+		config.Name = target
+		config.Fields = graphql.Fields{}
+		config.ResolveType = m.resolveType(src, config.ResolveType)
+		ctx.interfaces[target] = graphql.NewInterface(config)
+		ctx.interfaceConfigs[target] = config
+	case "object":
+		config := src.objectConfigs[source.name]
+		config.Name = target
+		config.Fields = graphql.Fields{}
+		if ifaces, ok := config.Interfaces.([]*graphql.Interface); ok {
+			merged := make([]*graphql.Interface, len(ifaces))
+			for i, iface := range ifaces {
+				merged[i] = m.typ(src, iface).(*graphql.Interface)
+			}
+			config.Interfaces = merged
+		}
+		ctx.objects[target] = graphql.NewObject(config)
+		ctx.objectConfigs[target] = config
+	case "union":
+		config := src.unionConfigs[source.name]
+		config.Name = target
+		if types, ok := config.Types.([]*graphql.Object); ok {
+			merged := make([]*graphql.Object, len(types))
+			for i, ob := range types {
+				merged[i] = m.typ(src, ob).(*graphql.Object)
+			}
+			config.Types = merged
+		}
+		config.ResolveType = m.resolveType(src, config.ResolveType)
+		ctx.unions[target] = graphql.NewUnion(config)
+		ctx.unionConfigs[target] = config
+	}
+}
+
+// fill adds the fields of the type of source to its type in ctx and returns the
+// conflicting root fields.
+func (m *merger) fill(ctx *Context, source mergeSource) []string {
+	src, target := source.ctx, source.ctx.mergedName(source.name)
+	var conflicts []string
+	switch source.kind {
+	case "input":
+		fields, _ := src.inputConfigs[source.name].Fields.(graphql.InputObjectConfigFieldMap)
+		merged := ctx.inputConfigs[target].Fields.(graphql.InputObjectConfigFieldMap)
+		for fieldName, field := range fields {
+			copied := *field
+			copied.Type = m.typ(src, field.Type).(graphql.Input)
+			merged[fieldName] = &copied
+		}
+	case "interface", "object":
+		var fields, merged graphql.Fields
+		if source.kind == "interface" {
+			fields = configFields(src.interfaceConfigs[source.name].Fields)
+			merged = configFields(ctx.interfaceConfigs[target].Fields)
+		} else {
+			fields = configFields(src.objectConfigs[source.name].Fields)
+			merged = configFields(ctx.objectConfigs[target].Fields)
+		}
+		for _, fieldName := range sortedKeys(fields) {
+			copied := m.field(src, fields[fieldName])
+			if existing, ok := merged[fieldName]; ok {
+				// Only root types are filled from several contexts
+				if printField(existing) != printField(copied) {
+					conflicts = append(conflicts, fmt.Sprintf("Conflicting definitions of field %s.%s.", target, fieldName))
+				}
+				continue
+			}
+			merged[fieldName] = copied
+			if rootTypeNames[target] && ctx == m.dst {
+				m.origins[target+"."+fieldName] = src
+			}
+		}
+	}
+	return conflicts
+}
+
+func (m *merger) field(src *Context, field *graphql.Field) *graphql.Field {
+	copied := *field
+	copied.Type = m.typ(src, field.Type).(graphql.Output)
+	if len(field.Args) > 0 {
+		copied.Args = make(graphql.FieldConfigArgument, len(field.Args))
+		for argName, arg := range field.Args {
+			copiedArg := *arg
+			copiedArg.Type = m.typ(src, arg.Type).(graphql.Input)
+			copied.Args[argName] = &copiedArg
+		}
+	}
+	return &copied
+}
+
+func printField(field *graphql.Field) string {
+	var b strings.Builder
+	printFields(&b, graphql.Fields{"field": field})
+	return b.String()
+}
+
+// mergeDirectives adds the directive definitions of src to the merged context and
+// returns the conflicting ones.
+func (m *merger) mergeDirectives(src *Context) []string {
+	var conflicts []string
+	for _, name := range sortedKeys(src.directiveConfigs) {
+		config := src.directiveConfigs[name]
+		if len(config.Args) > 0 {
+			args := make(graphql.FieldConfigArgument, len(config.Args))
+			for argName, arg := range config.Args {
+				copiedArg := *arg
+				copiedArg.Type = m.typ(src, arg.Type).(graphql.Input)
+				args[argName] = &copiedArg
+			}
+			config.Args = args
+		}
+		if existing, ok := m.dst.directiveConfigs[name]; ok {
+			if printDirective(existing) != printDirective(config) {
+				conflicts = append(conflicts, fmt.Sprintf("Conflicting definitions of directive @%s.", name))
+			}
+			continue
+		}
+		m.dst.directives[name] = graphql.NewDirective(config)
+		m.dst.directiveConfigs[name] = config
+	}
+	return conflicts
+}
+
+// mergeMiddlewares adds the middlewares and constraints of the types and root fields
+// taken from src to the merged context. The middlewares src uses for all fields and
+// for its root types become middlewares of its objects and root fields.
+func (m *merger) mergeMiddlewares(src *Context) {
+	owns := func(typeName, fieldName string) bool {
+		if rootTypeNames[typeName] {
+			return m.origins[typeName+"."+fieldName] == src
+		}
+		return m.origins[src.mergedName(typeName)] == src
+	}
+
+	for key, middlewares := range src.middlewares {
+		typeName, _, isField := strings.Cut(key, ".")
+		_, isObject := src.objectConfigs[typeName]
+		if rootTypeNames[typeName] || !owns(typeName, "") || (isObject && !isField) {
+			continue // Combined with the middlewares of all fields below
+		}
+		m.dst.middlewares[src.mergedName(typeName)+strings.TrimPrefix(key, typeName)] = middlewares
+	}
+	for _, name := range sortedKeys(src.objectConfigs) {
+		if !rootTypeNames[name] {
+			if owns(name, "") {
+				m.addMiddlewares(src.mergedName(name), src.use, src.middlewares[name])
+			}
+			continue
+		}
+		for fieldName := range configFields(src.objectConfigs[name].Fields) {
+			if owns(name, fieldName) {
+				m.addMiddlewares(name+"."+fieldName, src.use, src.middlewares[name], src.middlewares[name+"."+fieldName])
+			}
+		}
+	}
+
+	for key, c := range src.constraints {
+		typeName, rest, _ := strings.Cut(key, ".")
+		fieldName, _, _ := strings.Cut(rest, "(")
+		if owns(typeName, fieldName) {
+			m.dst.constraints[src.mergedName(typeName)+"."+rest] = c
+		}
+	}
+}
+
+func (m *merger) addMiddlewares(key string, middlewares ...[]FieldMiddleware) {
+	var combined []FieldMiddleware
+	for _, list := range middlewares {
+		combined = append(combined, list...)
+	}
+	if len(combined) > 0 {
+		m.dst.middlewares[key] = combined
+	}
+}
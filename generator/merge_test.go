@@ -0,0 +1,128 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func generateAll(t *testing.T, gqls ...string) []*Context {
+	ctxs := make([]*Context, len(gqls))
+	for i, gql := range gqls {
+		ctx, err := Generate(gql)
+		if err != nil {
+			fmt.Print(err)
+			t.FailNow()
+		}
+		ctxs[i] = ctx
+	}
+	return ctxs
+}
+
+func TestMerge(t *testing.T) {
+	ctxs := generateAll(t, `
+type Query {
+	user: User
+}
+type User {
+	id: ID!
+	name: String
+}`, `
+type Query {
+	invoice: Invoice
+	user: User
+}
+type Invoice {
+	total(currency: Currency = CHF): Float
+	customer: User
+}
+enum Currency {
+	CHF
+	EUR
+}
+type User {
+	id: ID!
+	name: String
+}`, `
+type Query {
+	legacyUser: User
+}
+type User {
+	id: Int
+}`)
+	users, invoices, legacy := ctxs[0], ctxs[1], ctxs[2]
+	users.SetResolver("Query", "user", func(p graphql.ResolveParams) (interface{}, error) {
+		return map[string]interface{}{"id": "1", "name": "Ada"}, nil
+	})
+	invoices.SetResolver("Query", "invoice", func(p graphql.ResolveParams) (interface{}, error) {
+		return map[string]interface{}{"total": 42.5, "customer": map[string]interface{}{"id": "2", "name": "Grace"}}, nil
+	})
+	legacy.SetResolver("Query", "legacyUser", func(p graphql.ResolveParams) (interface{}, error) {
+		return map[string]interface{}{"id": 3}, nil
+	})
+	var calls []string
+	legacy.Use(func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (interface{}, error) {
+			calls = append(calls, p.Info.ParentType.Name()+"."+p.Info.FieldName)
+			return next(p)
+		}
+	})
+
+	if _, err := Merge(users, invoices, legacy); err == nil || err.Error() != "Conflicting definitions of type User." {
+		t.Errorf("Expected conflict of User, got %v", err)
+	}
+
+	merged, err := Merge(users, invoices, legacy.Prefix("Legacy"))
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	expected := []string{"Currency", "Invoice", "LegacyUser", "Query", "User"}
+	if names := merged.TypeNames(); fmt.Sprint(names) != fmt.Sprint(expected) {
+		t.Errorf("Expected types %v, got %v", expected, names)
+	}
+	schema, err := CreateSchemaFromContext(merged)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ user { name } invoice { total customer { name } } legacyUser { __typename id } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	data, _ := json.Marshal(result.Data)
+	expectedData := `{"invoice":{"customer":{"name":"Grace"},"total":42.5},"legacyUser":{"__typename":"LegacyUser","id":3},"user":{"name":"Ada"}}`
+	if string(data) != expectedData {
+		t.Errorf("Expected %s, got %s", expectedData, data)
+	}
+
+	// The middlewares of a context only wrap the fields taken from it
+	sort.Strings(calls)
+	if fmt.Sprint(calls) != "[LegacyUser.id Query.legacyUser]" {
+		t.Errorf("Unexpected middleware calls: %v", calls)
+	}
+}
+
+func TestMergeConflicts(t *testing.T) {
+	ctxs := generateAll(t, `
+directive @cached(ttl: Int) on FIELD_DEFINITION
+type Query {
+	a: String
+}`, `
+directive @cached(ttl: String) on FIELD_DEFINITION
+type Query {
+	a: Int
+}`)
+
+	_, err := Merge(ctxs...)
+	expected := "Conflicting definitions of field Query.a.\nConflicting definitions of directive @cached."
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected error %q, got %v", expected, err)
+	}
+}
@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+// FieldMiddleware wraps the resolve function of a field.
+type FieldMiddleware func(next graphql.FieldResolveFn) graphql.FieldResolveFn
+
+// Use wraps the resolve functions of all fields of the objects of the context with
+// middlewares, including fields using the default resolver. The first middleware is
+// the outermost. The parent type and field name of a call are available from
+// ResolveParams.Info. Like the middlewares of directives, they are applied by
+// CreateSchemaFromContext around the final resolve functions.
+func (g *Context) Use(middlewares ...FieldMiddleware) *Context {
+	g.use = append(g.use, middlewares...)
+	if g.wrapped {
+		g.wrapResolvers()
+	}
+	return g
+}
+
+// wrapResolvers wraps the resolve functions of the fields of the objects of ctx with
+// the middlewares registered for them.
+func (g *Context) wrapResolvers() {
+	g.wrapped = true
+	for _, name := range sortedKeys(g.objects) {
+		for fieldName, fieldDef := range g.objects[name].Fields() {
+			g.wrapResolver(name, fieldName, fieldDef)
+		}
+	}
+}
+
+// wrapResolver wraps the resolve function of a field with the middlewares of the
+// context, of its object, of the interfaces of the object declaring the field, of the
+// field and, if they have any, with the check of the constraints of its arguments.
+// The unwrapped resolve function is kept so that the field can be wrapped again.
+func (g *Context) wrapResolver(typeName, fieldName string, fieldDef *graphql.FieldDefinition) {
+	middlewares := append([]FieldMiddleware{}, g.use...)
+	middlewares = append(middlewares, g.middlewares[typeName]...)
+	for _, iface := range g.objects[typeName].Interfaces() {
+		if _, ok := iface.Fields()[fieldName]; ok {
+			middlewares = append(middlewares, g.middlewares[iface.Name()]...)
+			middlewares = append(middlewares, g.middlewares[iface.Name()+"."+fieldName]...)
+			g.inheritConstraints(iface.Name(), typeName, fieldName)
+		}
+	}
+	middlewares = append(middlewares, g.middlewares[typeName+"."+fieldName]...)
+	if g.hasConstraints(typeName, fieldDef) {
+		middlewares = append(middlewares, g.checkConstraints)
+	}
+	if len(middlewares) == 0 {
+		return
+	}
+
+	resolve, ok := g.resolvers[fieldDef]
+	if !ok {
+		resolve = fieldDef.Resolve
+		g.resolvers[fieldDef] = resolve
+	}
+	if resolve == nil {
+		resolve = graphql.DefaultResolveFn
+	}
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		resolve = middlewares[i](resolve)
+	}
+	fieldDef.Resolve = resolve
+}
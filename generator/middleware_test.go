@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestUse(t *testing.T) {
+	gql := `
+type Query {
+	user: User
+}
+type User {
+	name: String
+}`
+
+	ctx, err := Generate(gql)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	var calls []string
+	ctx.Use(func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (interface{}, error) {
+			calls = append(calls, p.Info.ParentType.Name()+"."+p.Info.FieldName)
+			return next(p)
+		}
+	})
+	schema, err := CreateSchemaFromContext(ctx)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	// Resolvers and middlewares added after schema creation are applied as well
+	ctx.SetResolver("Query", "user", func(p graphql.ResolveParams) (interface{}, error) {
+		return map[string]interface{}{"name": "Ada"}, nil
+	})
+	ctx.Use(func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (interface{}, error) {
+			result, err := next(p)
+			if s, ok := result.(string); ok {
+				result = s + "!"
+			}
+			return result, err
+		}
+	})
+
+	r := graphql.Do(graphql.Params{Schema: schema, RequestString: `{ user { name } }`})
+	if fmt.Sprint(r.Data) != "map[user:map[name:Ada!]]" {
+		t.Errorf("Unexpected result: %v %v", r.Data, r.Errors)
+	}
+	sort.Strings(calls)
+	if fmt.Sprint(calls) != "[Query.user User.name]" {
+		t.Errorf("Unexpected middleware calls: %v", calls)
+	}
+}
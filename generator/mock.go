@@ -0,0 +1,258 @@
+package generator
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// ExampleDirective gives the mocked value of a field, e.g. `name: String
+// @example(value: "Ada")`. One item is picked of list values given for fields which
+// are no lists.
+const ExampleDirective = "example"
+
+// MockFn returns the mocked value of a type. For objects it returns the source of
+// their fields, e.g. a map.
+type MockFn func(rnd *rand.Rand) interface{}
+
+// MockOptions configures Context.Mock.
+type MockOptions struct {
+	// Seed seeds the mocked values. The same seed mocks the same values.
+	Seed int64
+
+	// ListLength is the length of mocked lists. It defaults to 2.
+	ListLength int
+
+	// Types overrides the mocked values of types by type name. Custom scalars are
+	// mocked as placeholder strings like DateTime-42 unless given here.
+	Types map[string]MockFn
+}
+
+var mockWords = []string{
+	"alpha", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel",
+	"india", "juliet", "kilo", "lima", "mike", "november", "oscar", "papa",
+}
+
+// mockObject is the source of the fields of a mocked object.
+type mockObject struct {
+	typeName string
+	seed     int64
+}
+
+type mocker struct {
+	ctx      *Context
+	opts     MockOptions
+	examples map[string]interface{}
+}
+
+// Mock sets resolvers returning deterministic fake data for all fields without
+// resolver and resolves the types of mocked interface and union values. Fields of
+// objects returned by real resolvers keep the default resolver. It is meant for
+// prototyping against the SDL before resolvers exist.
+func (g *Context) Mock(opts MockOptions) *Context {
+	if opts.ListLength == 0 {
+		opts.ListLength = 2
+	}
+	m := &mocker{ctx: g, opts: opts, examples: g.examples()}
+
+	for _, typeName := range sortedKeys(g.objectConfigs) {
+		fields := configFields(g.objectConfigs[typeName].Fields)
+		for _, fieldName := range sortedKeys(fields) {
+			field := fields[fieldName]
+			if field.Resolve != nil || field.Subscribe != nil {
+				continue
+			}
+			g.SetResolver(typeName, fieldName, m.resolver(typeName, fieldName, field.Type))
+		}
+	}
+	for _, name := range sortedKeys(g.interfaceConfigs) {
+		g.SetResolveType(name, m.resolveType(g.interfaceConfigs[name].ResolveType))
+	}
+	for _, name := range sortedKeys(g.unionConfigs) {
+		g.SetResolveType(name, m.resolveType(g.unionConfigs[name].ResolveType))
+	}
+	return g
+}
+
+// examples collects the values of @example by Type.field.
+func (g *Context) examples() map[string]interface{} {
+	examples := make(map[string]interface{})
+	if g.document == nil {
+		return examples
+	}
+	for _, def := range g.document.Definitions {
+		var obdef *ast.ObjectDefinition
+		switch def.(type) {
+		case *ast.ObjectDefinition:
+			obdef = def.(*ast.ObjectDefinition)
+		case *ast.TypeExtensionDefinition:
+			obdef = def.(*ast.TypeExtensionDefinition).Definition
+		default:
+			continue
+		}
+		for _, fieldDef := range obdef.Fields {
+			for _, directive := range fieldDef.Directives {
+				if directive.Name.Value != ExampleDirective {
+					continue
+				}
+				for _, arg := range directive.Arguments {
+					if arg.Name.Value == "value" {
+						examples[obdef.Name.Value+"."+fieldDef.Name.Value] = astValue(arg.Value)
+					}
+				}
+			}
+		}
+	}
+	return examples
+}
+
+func mockSeed(seed int64, keys ...interface{}) int64 {
+	h := fnv.New64a()
+	fmt.Fprint(h, seed)
+	for _, key := range keys {
+		fmt.Fprintf(h, "/%v", key)
+	}
+	return int64(h.Sum64())
+}
+
+func (m *mocker) resolver(typeName, fieldName string, typ graphql.Type) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		var seed int64
+		switch source, ok := p.Source.(mockObject); {
+		case ok:
+			seed = mockSeed(source.seed, fieldName)
+		case rootTypeNames[typeName]:
+			seed = mockSeed(m.opts.Seed, typeName, fieldName)
+		default:
+			// Objects of real resolvers are resolved as usual
+			return graphql.DefaultResolveFn(p)
+		}
+
+		if example, ok := m.examples[typeName+"."+fieldName]; ok {
+			items, isList := example.([]interface{})
+			if _, listType := nullableType(typ).(*graphql.List); isList && !listType && len(items) > 0 {
+				return m.coerce(typ, items[rand.New(rand.NewSource(seed)).Intn(len(items))]), nil
+			}
+			return m.coerce(typ, example), nil
+		}
+		return m.value(typ, seed), nil
+	}
+}
+
+func nullableType(typ graphql.Type) graphql.Type {
+	if nonNull, ok := typ.(*graphql.NonNull); ok {
+		return nonNull.OfType
+	}
+	return typ
+}
+
+// coerce turns example enum values, given by name, into their values.
+func (m *mocker) coerce(typ graphql.Type, example interface{}) interface{} {
+	switch t := nullableType(typ).(type) {
+	case *graphql.List:
+		if items, ok := example.([]interface{}); ok {
+			coerced := make([]interface{}, len(items))
+			for i, item := range items {
+				coerced[i] = m.coerce(t.OfType, item)
+			}
+			return coerced
+		}
+	case *graphql.Enum:
+		for _, value := range t.Values() {
+			if value.Name == example {
+				return value.Value
+			}
+		}
+	}
+	return example
+}
+
+// value returns the mocked value of typ for the given seed.
+func (m *mocker) value(typ graphql.Type, seed int64) interface{} {
+	typ = nullableType(typ)
+	rnd := rand.New(rand.NewSource(seed))
+	if mock, ok := m.opts.Types[typ.Name()]; ok {
+		return mock(rnd)
+	}
+
+	switch t := typ.(type) {
+	case *graphql.List:
+		items := make([]interface{}, m.opts.ListLength)
+		for i := range items {
+			items[i] = m.value(t.OfType, mockSeed(seed, i))
+		}
+		return items
+	case *graphql.Enum:
+		// The values of graphql-go enums are in map order
+		values := append([]*graphql.EnumValueDefinition{}, t.Values()...)
+		sort.Slice(values, func(i, j int) bool { return values[i].Name < values[j].Name })
+		if len(values) == 0 {
+			return nil
+		}
+		return values[rnd.Intn(len(values))].Value
+	case *graphql.Object:
+		return mockObject{typeName: t.Name(), seed: seed}
+	case *graphql.Interface, *graphql.Union:
+		implementations := m.implementations(typ.Name())
+		if len(implementations) == 0 {
+			return nil
+		}
+		return mockObject{typeName: implementations[rnd.Intn(len(implementations))], seed: seed}
+	}
+
+	switch typ.Name() {
+	case "Int":
+		return rnd.Intn(100)
+	case "Float":
+		return float64(rnd.Intn(10000)) / 100
+	case "Boolean":
+		return rnd.Intn(2) == 0
+	case "ID":
+		return fmt.Sprint(rnd.Intn(1000000))
+	case "String":
+		return mockWords[rnd.Intn(len(mockWords))] + " " + mockWords[rnd.Intn(len(mockWords))]
+	}
+	if _, ok := typ.(*graphql.Scalar); ok {
+		// A placeholder for custom scalars without a mock in Types
+		return fmt.Sprintf("%s-%d", typ.Name(), rnd.Intn(1000))
+	}
+	return nil
+}
+
+// implementations returns the objects implementing an interface or belonging to a union.
+func (m *mocker) implementations(abstractName string) []string {
+	var names []string
+	if config, ok := m.ctx.unionConfigs[abstractName]; ok {
+		types, _ := config.Types.([]*graphql.Object)
+		for _, ob := range types {
+			names = append(names, ob.Name())
+		}
+	}
+	for _, name := range sortedKeys(m.ctx.objectConfigs) {
+		ifaces, _ := m.ctx.objectConfigs[name].Interfaces.([]*graphql.Interface)
+		for _, iface := range ifaces {
+			if iface.Name() == abstractName {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolveType resolves the type of mocked objects and falls back to resolveType.
+func (m *mocker) resolveType(resolveType graphql.ResolveTypeFn) graphql.ResolveTypeFn {
+	return func(p graphql.ResolveTypeParams) *graphql.Object {
+		if mock, ok := p.Value.(mockObject); ok {
+			return m.ctx.objects[mock.typeName]
+		}
+		if resolveType != nil {
+			return resolveType(p)
+		}
+		return nil
+	}
+}
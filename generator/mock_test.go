@@ -0,0 +1,155 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestMock(t *testing.T) {
+	gql := `
+enum Role {
+	ADMIN
+	USER
+}
+enum Level {
+	A
+	B
+	C
+	D
+	E
+	F
+	G
+	H
+}
+interface Named {
+	name: String
+}
+type User implements Named {
+	id: ID!
+	name: String @example(value: ["Ada", "Grace"])
+	role: Role! @example(value: ADMIN)
+	age: Int
+	tags: [String!]!
+	created: DateTime!
+	levels: [Level!]!
+}
+scalar DateTime
+type Bot implements Named {
+	name: String
+	version: Float
+}
+union Result = User | Bot
+type Query {
+	me: User
+	search: [Result!]!
+	named: Named
+	real: User
+	version: Float
+}`
+
+	mockSchema := func() graphql.Schema {
+		ctx, err := Generate(gql)
+		if err != nil {
+			fmt.Print(err)
+			t.FailNow()
+		}
+		ctx.SetResolver("Query", "real", func(p graphql.ResolveParams) (interface{}, error) {
+			return map[string]interface{}{"id": "1", "name": "Real", "role": 1, "tags": []string{}}, nil
+		})
+		ctx.Mock(MockOptions{
+			Seed:       1,
+			ListLength: 3,
+			Types: map[string]MockFn{
+				"Float": func(rnd *rand.Rand) interface{} { return 1.5 },
+			},
+		})
+		if fields := ctx.UnresolvedFields(); len(fields) > 0 {
+			t.Errorf("Unexpected unresolved fields: %v", fields)
+		}
+		schema, err := CreateSchemaFromContext(ctx)
+		if err != nil {
+			fmt.Print(err)
+			t.FailNow()
+		}
+		return schema
+	}
+
+	query := `{
+	me { id name role age tags created levels }
+	search { __typename ... on Named { name } }
+	named { __typename }
+	real { name role }
+	version
+}`
+	run := func(schema graphql.Schema) map[string]interface{} {
+		result := graphql.Do(graphql.Params{Schema: schema, RequestString: query})
+		if len(result.Errors) > 0 {
+			t.Fatalf("Unexpected errors: %v", result.Errors)
+		}
+		return result.Data.(map[string]interface{})
+	}
+	schema := mockSchema()
+	data := run(schema)
+	first, _ := json.Marshal(data)
+	second, _ := json.Marshal(run(schema))
+	if string(first) != string(second) {
+		t.Errorf("Expected the same data for the same seed:\n%s\n%s", first, second)
+	}
+	// Enum values must not depend on the map order of a generated schema
+	for i := 0; i < 5; i++ {
+		other, _ := json.Marshal(run(mockSchema()))
+		if string(first) != string(other) {
+			t.Fatalf("Expected the same data for the same seed in another schema:\n%s\n%s", first, other)
+		}
+	}
+
+	me := data["me"].(map[string]interface{})
+	if name := me["name"]; name != "Ada" && name != "Grace" {
+		t.Errorf("Expected an example name, got %v", name)
+	}
+	if me["role"] != "ADMIN" {
+		t.Errorf("Expected the example role, got %v", me["role"])
+	}
+	if _, ok := me["id"].(string); !ok {
+		t.Errorf("Expected a mocked ID, got %v", me["id"])
+	}
+	if created, _ := me["created"].(string); !strings.HasPrefix(created, "DateTime-") {
+		t.Errorf("Expected a placeholder for the custom scalar, got %v", me["created"])
+	}
+	if tags := me["tags"].([]interface{}); len(tags) != 3 {
+		t.Errorf("Expected 3 tags, got %v", tags)
+	}
+	for _, result := range data["search"].([]interface{}) {
+		if typeName := result.(map[string]interface{})["__typename"]; typeName != "User" && typeName != "Bot" {
+			t.Errorf("Unexpected type of search result %v", typeName)
+		}
+	}
+	if data["named"] == nil {
+		t.Errorf("Expected a mocked interface value")
+	}
+	if real := data["real"].(map[string]interface{}); real["name"] != "Real" || real["role"] != "USER" {
+		t.Errorf("Expected the fields of real objects to be resolved by default, got %v", real)
+	}
+	if data["version"] != 1.5 {
+		t.Errorf("Expected the overridden Float, got %v", data["version"])
+	}
+
+	other, err := Generate(gql)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	other.Mock(MockOptions{Seed: 2})
+	otherSchema, _ := CreateSchemaFromContext(other)
+	result := graphql.Do(graphql.Params{Schema: otherSchema, RequestString: `{ me { id age tags } }`})
+	otherData, _ := json.Marshal(result.Data)
+	firstMe, _ := json.Marshal(map[string]interface{}{"me": map[string]interface{}{"id": me["id"], "age": me["age"], "tags": me["tags"]}})
+	if string(otherData) == string(firstMe) {
+		t.Errorf("Expected different data for a different seed: %s", otherData)
+	}
+}
@@ -0,0 +1,371 @@
+package generator
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/graphql-go/graphql"
+)
+
+// rootTypeNames are the names of the operation types. They get no model struct.
+var rootTypeNames = map[string]bool{
+	"Query":        true,
+	"Mutation":     true,
+	"Subscription": true,
+}
+
+// initialisms are written in upper case in Go identifiers.
+var initialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true,
+	"JSON": true, "SQL": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// goName turns a GraphQL name into an exported Go identifier, e.g. userId into UserID
+// and SUPER_ADMIN into SuperAdmin.
+func goName(name string) string {
+	var words []string
+	if strings.ToUpper(name) == name {
+		for _, word := range strings.Split(name, "_") {
+			if word != "" {
+				words = append(words, strings.ToLower(word))
+			}
+		}
+	} else {
+		start := 0
+		runes := []rune(name)
+		for i := 1; i <= len(runes); i++ {
+			if i == len(runes) || runes[i] == '_' || unicode.IsUpper(runes[i]) && !unicode.IsUpper(runes[i-1]) {
+				if word := strings.Trim(string(runes[start:i]), "_"); word != "" {
+					words = append(words, word)
+				}
+				start = i
+			}
+		}
+	}
+
+	var b strings.Builder
+	for _, word := range words {
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(word)
+		b.WriteRune(unicode.ToUpper(runes[0]))
+		b.WriteString(string(runes[1:]))
+	}
+	return b.String()
+}
+
+// goTypeOf returns the Go type of values of typ. Nullable scalars, enums and
+// objects become pointers, nullable lists stay slices.
+func (c *modelCodegen) goTypeOf(typ graphql.Type) string {
+	if nonNull, ok := typ.(*graphql.NonNull); ok {
+		return c.goTypeOfNonNull(nonNull.OfType)
+	}
+	goType := c.goTypeOfNonNull(typ)
+	switch typ.(type) {
+	case *graphql.List, *graphql.Interface, *graphql.Union:
+		return goType
+	}
+	if goType == "interface{}" {
+		return goType
+	}
+	return "*" + goType
+}
+
+func (c *modelCodegen) goTypeOfNonNull(typ graphql.Type) string {
+	switch typ.(type) {
+	case *graphql.List:
+		return "[]" + c.goTypeOf(typ.(*graphql.List).OfType)
+	case *graphql.Scalar:
+		switch typ.Name() {
+		case "String", "ID":
+			return "string"
+		case "Int":
+			return "int"
+		case "Float":
+			return "float64"
+		case "Boolean":
+			return "bool"
+		}
+		if goType, ok := c.config.Scalars[typ.Name()]; ok {
+			return c.qualify(goType)
+		}
+		return "interface{}"
+	}
+	return goName(typ.Name())
+}
+
+// qualify turns a type of the form import/path.Type into pkg.Type and imports the package.
+func (c *modelCodegen) qualify(goType string) string {
+	dot := strings.LastIndex(goType, ".")
+	if dot < 0 {
+		return goType
+	}
+	importPath := goType[:dot]
+	c.file.use(importPath)
+	return path.Base(importPath) + goType[dot:]
+}
+
+type modelCodegen struct {
+	ctx    *Context
+	config CodegenConfig
+	file   *goFile
+}
+
+// generateModels emits the model structs, enum constants and argument structs of ctx into file.
+func generateModels(ctx *Context, config CodegenConfig, file *goFile) {
+	c := &modelCodegen{ctx: ctx, config: config, file: file}
+
+	for _, name := range ctx.TypeNames() {
+		switch {
+		case ctx.enums[name] != nil:
+			c.enum(name)
+		case ctx.interfaces[name] != nil:
+			c.abstract(name, ctx.interfaceConfigs[name].Description)
+		case ctx.unions[name] != nil:
+			c.abstract(name, ctx.unionConfigs[name].Description)
+		case ctx.inputs[name] != nil:
+			c.input(name)
+		case ctx.objects[name] != nil:
+			if !rootTypeNames[name] {
+				c.object(name)
+			}
+			c.args(name)
+		}
+	}
+}
+
+func (c *modelCodegen) comment(indent, name, description string) {
+	if description == "" {
+		return
+	}
+	for i, line := range strings.Split(description, "\n") {
+		if i == 0 {
+			line = name + " " + line
+		}
+		c.file.printf("%s// %s\n", indent, strings.TrimRight(line, " \t"))
+	}
+}
+
+func (c *modelCodegen) field(name, description string, typ graphql.Type) {
+	c.comment("\t", goName(name), description)
+	c.file.printf("\t%s %s `json:\"%s\"`\n", goName(name), c.goTypeOf(typ), name)
+}
+
+func (c *modelCodegen) enum(name string) {
+	typeName := goName(name)
+	c.comment("", typeName, c.ctx.enumConfigs[name].Description)
+	c.file.printf("type %s string\n\n", typeName)
+
+	values := c.ctx.enumValues(name)
+	if len(values) == 0 {
+		return
+	}
+	c.file.printf("const (\n")
+	for _, value := range values {
+		c.comment("\t", typeName+goName(value.Name), value.Description)
+		c.file.printf("\t%s%s %s = %q\n", typeName, goName(value.Name), typeName, value.Name)
+	}
+	c.file.printf(")\n\n")
+}
+
+// abstract emits an interface for interfaces and unions which is implemented by a
+// marker method of each possible object.
+func (c *modelCodegen) abstract(name, description string) {
+	typeName := goName(name)
+	c.comment("", typeName, description)
+	c.file.printf("type %s interface {\n\tIs%s()\n}\n\n", typeName, typeName)
+}
+
+func (c *modelCodegen) object(name string) {
+	config := c.ctx.objectConfigs[name]
+	typeName := goName(name)
+	c.comment("", typeName, config.Description)
+	c.file.printf("type %s struct {\n", typeName)
+	fields := configFields(config.Fields)
+	for _, fieldName := range sortedKeys(fields) {
+		c.field(fieldName, fields[fieldName].Description, fields[fieldName].Type)
+	}
+	c.file.printf("}\n\n")
+
+	ifaces, _ := config.Interfaces.([]*graphql.Interface)
+	for _, iface := range ifaces {
+		c.file.printf("func (%s) Is%s() {}\n\n", typeName, goName(iface.Name()))
+	}
+	for _, unionName := range sortedKeys(c.ctx.unionConfigs) {
+		types, _ := c.ctx.unionConfigs[unionName].Types.([]*graphql.Object)
+		for _, member := range types {
+			if member.Name() == name {
+				c.file.printf("func (%s) Is%s() {}\n\n", typeName, goName(unionName))
+			}
+		}
+	}
+}
+
+func (c *modelCodegen) input(name string) {
+	config := c.ctx.inputConfigs[name]
+	typeName := goName(name)
+	c.comment("", typeName, config.Description)
+	c.file.printf("type %s struct {\n", typeName)
+	fields, _ := config.Fields.(graphql.InputObjectConfigFieldMap)
+	for _, fieldName := range sortedKeys(fields) {
+		c.field(fieldName, fields[fieldName].Description, fields[fieldName].Type)
+	}
+	c.file.printf("}\n\n")
+}
+
+// argsTypeName returns the name of the struct holding the arguments of a field.
+func argsTypeName(typeName, fieldName string) string {
+	return goName(typeName) + goName(fieldName) + "Args"
+}
+
+// args emits a struct and a typed decoder for the arguments of each field of the
+// object which takes arguments.
+func (c *modelCodegen) args(name string) {
+	fields := configFields(c.ctx.objectConfigs[name].Fields)
+	for _, fieldName := range sortedKeys(fields) {
+		args := fields[fieldName].Args
+		if len(args) == 0 {
+			continue
+		}
+		typeName := argsTypeName(name, fieldName)
+
+		c.file.printf("// %s are the arguments of %s.%s.\n", typeName, name, fieldName)
+		c.file.printf("type %s struct {\n", typeName)
+		for _, argName := range sortedKeys(args) {
+			c.field(argName, args[argName].Description, args[argName].Type)
+		}
+		c.file.printf("}\n\n")
+
+		c.file.use("github.com/graphql-go/graphql")
+		c.file.use(ImportPath)
+		c.file.printf("// Decode%s decodes the arguments of a resolve call of %s.%s.\n", typeName, name, fieldName)
+		c.file.printf("func Decode%s(p graphql.ResolveParams) (%s, error) {\n", typeName, typeName)
+		c.file.printf("\tvar args %s\n", typeName)
+		c.file.printf("\terr := generator.DecodeArgs(p, &args)\n")
+		c.file.printf("\treturn args, err\n")
+		c.file.printf("}\n\n")
+	}
+}
+
+// generateEnumValueMap emits the EnumValues option mapping the enum values to the
+// enum constants, so that resolvers can return the constants.
+func generateEnumValueMap(ctx *Context, file *goFile) {
+	file.printf("// EnumValues maps the values of the enums of Schema to the enum constants.\n")
+	file.printf("var EnumValues = map[string]map[string]interface{}{\n")
+	for _, name := range sortedKeys(ctx.enumConfigs) {
+		file.printf("\t%q: {\n", name)
+		for _, value := range ctx.enumValues(name) {
+			file.printf("\t\t%q: %s%s,\n", value.Name, goName(name), goName(value.Name))
+		}
+		file.printf("\t},\n")
+	}
+	file.printf("}\n\n")
+}
+
+// goScope records the Go identifiers declared in a scope and what they were
+// declared for.
+type goScope map[string]string
+
+func (scope goScope) declare(goIdent, what string) error {
+	if other, ok := scope[goIdent]; ok {
+		return fmt.Errorf("Codegen: %s and %s both map to the Go identifier %s.", other, what, goIdent)
+	}
+	scope[goIdent] = what
+	return nil
+}
+
+// checkModelNames reports Go identifiers which the code generated for config would
+// declare twice, in the package or in a struct, e.g. for a type named Schema.
+func checkModelNames(ctx *Context, config CodegenConfig) error {
+	var err error
+	pkg := goScope{}
+	members := make(map[string]goScope)
+	declare := func(scope goScope, goIdent, what string) {
+		if err == nil {
+			err = scope.declare(goIdent, what)
+		}
+	}
+	member := func(typeName, goIdent, what string) {
+		if members[typeName] == nil {
+			members[typeName] = goScope{}
+		}
+		declare(members[typeName], goIdent, what)
+	}
+
+	declare(pkg, "Schema", "the constant Schema")
+	declare(pkg, "NewContext", "the function NewContext")
+	if !config.Models {
+		return err
+	}
+	declare(pkg, "EnumValues", "the variable EnumValues")
+
+	for _, name := range ctx.TypeNames() {
+		switch {
+		case ctx.enums[name] != nil:
+			declare(pkg, goName(name), "type "+name)
+			for _, value := range ctx.enumValues(name) {
+				declare(pkg, goName(name)+goName(value.Name), "enum value "+name+"."+value.Name)
+			}
+		case ctx.interfaces[name] != nil, ctx.unions[name] != nil:
+			declare(pkg, goName(name), "type "+name)
+		case ctx.inputs[name] != nil:
+			declare(pkg, goName(name), "type "+name)
+			fields, _ := ctx.inputConfigs[name].Fields.(graphql.InputObjectConfigFieldMap)
+			for _, fieldName := range sortedKeys(fields) {
+				member(name, goName(fieldName), "field "+name+"."+fieldName)
+			}
+		case ctx.objects[name] != nil:
+			config := ctx.objectConfigs[name]
+			if !rootTypeNames[name] {
+				declare(pkg, goName(name), "type "+name)
+			}
+			fields := configFields(config.Fields)
+			for _, fieldName := range sortedKeys(fields) {
+				if !rootTypeNames[name] {
+					member(name, goName(fieldName), "field "+name+"."+fieldName)
+				}
+				args := fields[fieldName].Args
+				if len(args) == 0 {
+					continue
+				}
+				argsType := argsTypeName(name, fieldName)
+				declare(pkg, argsType, "the arguments of "+name+"."+fieldName)
+				declare(pkg, "Decode"+argsType, "the decoder of the arguments of "+name+"."+fieldName)
+				for _, argName := range sortedKeys(args) {
+					member(argsType, goName(argName), "argument "+name+"."+fieldName+"."+argName)
+				}
+			}
+			ifaces, _ := config.Interfaces.([]*graphql.Interface)
+			for _, iface := range ifaces {
+				member(name, "Is"+goName(iface.Name()), "the marker method of "+iface.Name())
+			}
+			for _, unionName := range sortedKeys(ctx.unionConfigs) {
+				types, _ := ctx.unionConfigs[unionName].Types.([]*graphql.Object)
+				for _, ob := range types {
+					if ob.Name() == name {
+						member(name, "Is"+goName(unionName), "the marker method of "+unionName)
+					}
+				}
+			}
+		}
+	}
+
+	if config.Resolvers {
+		declare(pkg, "Resolvers", "the type Resolvers")
+		declare(pkg, "Bind", "the function Bind")
+		declare(pkg, "NewResolvers", "the function NewResolvers")
+		for _, name := range resolvedTypeNames(ctx) {
+			declare(pkg, resolverName(name), "the resolver interface of "+name)
+			if rootTypeNames[name] {
+				declare(pkg, goName(name)+"Stub", "the stub resolver of "+name)
+			} else {
+				declare(pkg, "source"+goName(name), "the source conversion of "+name)
+			}
+		}
+	}
+	return err
+}
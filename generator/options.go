@@ -0,0 +1,44 @@
+package generator
+
+// Generator generates Contexts from SDL. The package level Generate functions use a
+// Generator without options.
+type Generator struct {
+	stringEnums bool
+	enumValues  map[string]map[string]interface{}
+	directives  map[string]DirectiveHandler
+	relayNodes  bool
+	federation  bool
+}
+
+// Option configures a Generator.
+type Option func(*Generator)
+
+// New returns a Generator configured by options.
+func New(options ...Option) *Generator {
+	gen := &Generator{
+		directives: map[string]DirectiveHandler{
+			"deprecated":        deprecatedDirective,
+			ConnectionDirective: connectionDirective,
+		},
+	}
+	for _, option := range options {
+		option(gen)
+	}
+	return gen
+}
+
+// StringEnums makes the values of generated enums their names instead of their
+// index, so that resolvers can return the names, e.g. as typed string constants.
+func StringEnums() Option {
+	return func(gen *Generator) {
+		gen.stringEnums = true
+	}
+}
+
+// EnumValues sets the values of enum values by enum name and value name, e.g. to
+// typed constants. Values which are not given keep their default.
+func EnumValues(values map[string]map[string]interface{}) Option {
+	return func(gen *Generator) {
+		gen.enumValues = values
+	}
+}
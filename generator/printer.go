@@ -0,0 +1,252 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/printer"
+)
+
+// TypeNames returns the names of all types of the context in alphabetical order.
+func (g *Context) TypeNames() []string {
+	var names []string
+	for name := range g.scalars {
+		names = append(names, name)
+	}
+	for name := range g.objects {
+		names = append(names, name)
+	}
+	for name := range g.interfaces {
+		names = append(names, name)
+	}
+	for name := range g.unions {
+		names = append(names, name)
+	}
+	for name := range g.inputs {
+		names = append(names, name)
+	}
+	for name := range g.enums {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PrintSchema prints the directive definitions and types of the context as normalized
+// SDL. Directives, types, fields and arguments are sorted by name, enum values keep
+// their declaration order.
+func PrintSchema(ctx *Context) string {
+	var blocks []string
+	for _, name := range sortedKeys(ctx.directiveConfigs) {
+		blocks = append(blocks, printDirective(ctx.directiveConfigs[name]))
+	}
+	for _, name := range ctx.TypeNames() {
+		typ, _ := ctx.GetObject(name)
+		blocks = append(blocks, printType(ctx, typ))
+	}
+	if len(blocks) == 0 {
+		return ""
+	}
+	return strings.Join(blocks, "\n\n") + "\n"
+}
+
+func printDirective(config graphql.DirectiveConfig) string {
+	var b strings.Builder
+	printDescription(&b, "", config.Description)
+	fmt.Fprintf(&b, "directive @%s%s on %s", config.Name, printArgs(config.Args), strings.Join(config.Locations, " | "))
+	return b.String()
+}
+
+// printType prints a type from its config, since graphql-go drops the fields of
+// types it rejects, e.g. fields of custom scalars without serialize function.
+func printType(ctx *Context, typ graphql.Type) string {
+	var b strings.Builder
+	printDescription(&b, "", typ.Description())
+
+	name := typ.Name()
+	switch typ.(type) {
+	case *graphql.Scalar:
+		fmt.Fprintf(&b, "scalar %s", name)
+	case *graphql.Object:
+		config := ctx.objectConfigs[name]
+		fmt.Fprintf(&b, "type %s", name)
+		if ifaces, _ := config.Interfaces.([]*graphql.Interface); len(ifaces) > 0 {
+			names := make([]string, len(ifaces))
+			for i, iface := range ifaces {
+				names[i] = iface.Name()
+			}
+			fmt.Fprintf(&b, " implements %s", strings.Join(names, " & "))
+		}
+		printFields(&b, configFields(config.Fields))
+	case *graphql.Interface:
+		fmt.Fprintf(&b, "interface %s", name)
+		printFields(&b, configFields(ctx.interfaceConfigs[name].Fields))
+	case *graphql.Union:
+		fmt.Fprintf(&b, "union %s", name)
+		if types, _ := ctx.unionConfigs[name].Types.([]*graphql.Object); len(types) > 0 {
+			names := make([]string, len(types))
+			for i, ob := range types {
+				names[i] = ob.Name()
+			}
+			fmt.Fprintf(&b, " = %s", strings.Join(names, " | "))
+		}
+	case *graphql.Enum:
+		fmt.Fprintf(&b, "enum %s", name)
+		values := ctx.enumValues(name)
+		if len(values) > 0 {
+			b.WriteString(" {\n")
+			for _, value := range values {
+				printDescription(&b, "  ", value.Description)
+				b.WriteString("  " + value.Name + printDeprecation(value.DeprecationReason) + "\n")
+			}
+			b.WriteString("}")
+		}
+	case *graphql.InputObject:
+		fmt.Fprintf(&b, "input %s", name)
+		fields, _ := ctx.inputConfigs[name].Fields.(graphql.InputObjectConfigFieldMap)
+		if len(fields) > 0 {
+			b.WriteString(" {\n")
+			for _, fieldName := range sortedKeys(fields) {
+				field := fields[fieldName]
+				printDescription(&b, "  ", field.Description)
+				fmt.Fprintf(&b, "  %s: %s%s\n", fieldName, field.Type, printDefault(field.DefaultValue))
+			}
+			b.WriteString("}")
+		}
+	}
+	return b.String()
+}
+
+// configFields returns the fields of an object or interface config.
+func configFields(fields interface{}) graphql.Fields {
+	switch fields.(type) {
+	case graphql.Fields:
+		return fields.(graphql.Fields)
+	case graphql.FieldsThunk:
+		return fields.(graphql.FieldsThunk)()
+	}
+	return nil
+}
+
+func printFields(b *strings.Builder, fields graphql.Fields) {
+	if len(fields) == 0 {
+		return
+	}
+	b.WriteString(" {\n")
+	for _, name := range sortedKeys(fields) {
+		field := fields[name]
+		printDescription(b, "  ", field.Description)
+		fmt.Fprintf(b, "  %s%s: %s%s\n", name, printArgs(field.Args), field.Type, printDeprecation(field.DeprecationReason))
+	}
+	b.WriteString("}")
+}
+
+func printArgs(args graphql.FieldConfigArgument) string {
+	if len(args) == 0 {
+		return ""
+	}
+	printed := make([]string, 0, len(args))
+	for _, name := range sortedKeys(args) {
+		printed = append(printed, fmt.Sprintf("%s: %s%s", name, args[name].Type, printDefault(args[name].DefaultValue)))
+	}
+	return "(" + strings.Join(printed, ", ") + ")"
+}
+
+func printDescription(b *strings.Builder, indent, description string) {
+	if description == "" {
+		return
+	}
+	if !strings.Contains(description, "\n") {
+		fmt.Fprintf(b, "%s%s\n", indent, printValue(description))
+		return
+	}
+	b.WriteString(indent + `"""` + "\n")
+	for _, line := range strings.Split(description, "\n") {
+		b.WriteString(indent + strings.ReplaceAll(line, `"""`, `\"""`) + "\n")
+	}
+	b.WriteString(indent + `"""` + "\n")
+}
+
+func printDeprecation(reason string) string {
+	if reason == "" {
+		return ""
+	}
+	if reason == graphql.DefaultDeprecationReason {
+		return " @deprecated"
+	}
+	return fmt.Sprintf(" @deprecated(reason: %s)", printValue(reason))
+}
+
+func printDefault(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return " = " + printValue(value)
+}
+
+// printValue prints a default value as GraphQL literal.
+func printValue(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case ast.Node:
+		return fmt.Sprintf("%v", printer.Print(value.(ast.Node)))
+	case string:
+		str, _ := json.Marshal(value)
+		return string(str)
+	case []interface{}:
+		list := value.([]interface{})
+		printed := make([]string, len(list))
+		for i, item := range list {
+			printed[i] = printValue(item)
+		}
+		return "[" + strings.Join(printed, ", ") + "]"
+	case map[string]interface{}:
+		object := value.(map[string]interface{})
+		printed := make([]string, 0, len(object))
+		for _, key := range sortedKeys(object) {
+			printed = append(printed, key+": "+printValue(object[key]))
+		}
+		return "{" + strings.Join(printed, ", ") + "}"
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// enumValue is a value of an enum config together with its name.
+type enumValue struct {
+	Name string
+	*graphql.EnumValueConfig
+}
+
+// enumValues returns the values of an enum in declaration order.
+func (g *Context) enumValues(which string) []enumValue {
+	config := g.enumConfigs[which]
+	values := make([]enumValue, 0, len(config.Values))
+	for name, value := range config.Values {
+		values = append(values, enumValue{name, value})
+	}
+
+	order := make(map[string]int)
+	if g.document != nil {
+		for _, def := range g.document.Definitions {
+			if edef, ok := def.(*ast.EnumDefinition); ok && edef.Name.Value == which {
+				for i, value := range edef.Values {
+					order[value.Name.Value] = i
+				}
+			}
+		}
+	}
+	sort.Slice(values, func(i, j int) bool {
+		oi, iok := order[values[i].Name]
+		oj, jok := order[values[j].Name]
+		if iok && jok {
+			return oi < oj
+		}
+		return values[i].Name < values[j].Name
+	})
+	return values
+}
@@ -0,0 +1,66 @@
+package generator
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPrintSchema(t *testing.T) {
+	gql := `
+type Query {
+	users(first: Int = 10, after: String): [User!]
+	node(id: ID!): Node
+}
+interface Node {
+	id: ID!
+}
+type User implements Node {
+	id: ID!
+	role: Role
+	born: Date
+}
+enum Role { USER, ADMIN }
+union Result = User
+scalar Date
+input UserInput {
+	name: String
+}`
+
+	expected := `scalar Date
+
+interface Node {
+  id: ID!
+}
+
+type Query {
+  node(id: ID!): Node
+  users(after: String, first: Int = 10): [User!]
+}
+
+union Result = User
+
+enum Role {
+  USER
+  ADMIN
+}
+
+type User implements Node {
+  born: Date
+  id: ID!
+  role: Role
+}
+
+input UserInput {
+  name: String
+}
+`
+
+	ctx, err := Generate(gql)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	if got := PrintSchema(ctx); got != expected {
+		t.Errorf("Unexpected SDL printed:\n%s", got)
+	}
+}
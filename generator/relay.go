@@ -0,0 +1,250 @@
+package generator
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// NodeFetcher fetches the object of a node type by its ID within the type.
+type NodeFetcher func(ctx context.Context, id string) (interface{}, error)
+
+// RelayNodes enables Relay global object identification. The Node interface and
+// the node(id: ID!) and nodes(ids: [ID!]!) fields of Query are generated unless the
+// SDL defines them. The id fields of objects implementing Node return global IDs
+// built from the ID their resolvers return, and node and nodes fetch objects by
+// global ID with the fetchers registered by Context.RegisterNode.
+//
+// The type of Node values is resolved automatically: objects fetched by node and
+// nodes are of the type of their fetcher. Other values are resolved by the
+// ResolveType function set with Context.SetResolveType, then by the IsTypeOf
+// functions of the node types, then by a "__typename" key of map values, and
+// finally to the only node type if there is just one.
+func RelayNodes() Option {
+	return func(gen *Generator) {
+		gen.relayNodes = true
+	}
+}
+
+// ToGlobalID returns the global ID of the object of a type with the given ID.
+func ToGlobalID(typeName, id string) string {
+	return base64.StdEncoding.EncodeToString([]byte(typeName + ":" + id))
+}
+
+// FromGlobalID returns the type and the ID within the type of a global ID.
+func FromGlobalID(globalID string) (typeName, id string, err error) {
+	decoded, err := base64.StdEncoding.DecodeString(globalID)
+	if err == nil {
+		var ok bool
+		if typeName, id, ok = strings.Cut(string(decoded), ":"); ok && typeName != "" {
+			return typeName, id, nil
+		}
+	}
+	return "", "", fmt.Errorf("Invalid global ID %q.", globalID)
+}
+
+// RegisterNode registers the fetcher of the objects of a type implementing Node.
+func (g *Context) RegisterNode(typeName string, fetch NodeFetcher) error {
+	if !g.isNodeType(typeName) {
+		return fmt.Errorf("Object %s does not implement Node.", typeName)
+	}
+	g.nodeFetchers[typeName] = fetch
+	return nil
+}
+
+func (g *Context) isNodeType(typeName string) bool {
+	config, ok := g.objectConfigs[typeName]
+	if !ok {
+		return false
+	}
+	ifaces, _ := config.Interfaces.([]*graphql.Interface)
+	for _, iface := range ifaces {
+		if iface.Name() == "Node" {
+			return true
+		}
+	}
+	return false
+}
+
+// expandNodes adds the Node interface and the node and nodes fields of Query to astDoc
+// unless it defines them.
+func expandNodes(astDoc *ast.Document) {
+	var query *ast.ObjectDefinition
+	hasNode := false
+	for _, def := range astDoc.Definitions {
+		switch def.(type) {
+		case *ast.ObjectDefinition:
+			if def.(*ast.ObjectDefinition).Name.Value == "Query" {
+				query = def.(*ast.ObjectDefinition)
+			}
+		case *ast.InterfaceDefinition:
+			hasNode = hasNode || def.(*ast.InterfaceDefinition).Name.Value == "Node"
+		}
+	}
+
+	if !hasNode {
+		astDoc.Definitions = append(astDoc.Definitions, ast.NewInterfaceDefinition(&ast.InterfaceDefinition{
+			Name:   ast.NewName(&ast.Name{Value: "Node"}),
+			Fields: []*ast.FieldDefinition{sdlField("id", astNonNull(astNamed("ID")))},
+		}))
+	}
+	if query == nil {
+		return
+	}
+	fields := map[string]*ast.FieldDefinition{
+		"node":  sdlField("node", astNamed("Node")),
+		"nodes": sdlField("nodes", astNonNull(ast.NewList(&ast.List{Type: astNamed("Node")}))),
+	}
+	fields["node"].Arguments = []*ast.InputValueDefinition{ast.NewInputValueDefinition(&ast.InputValueDefinition{
+		Name: ast.NewName(&ast.Name{Value: "id"}),
+		Type: astNonNull(astNamed("ID")),
+	})}
+	fields["nodes"].Arguments = []*ast.InputValueDefinition{ast.NewInputValueDefinition(&ast.InputValueDefinition{
+		Name: ast.NewName(&ast.Name{Value: "ids"}),
+		Type: astNonNull(ast.NewList(&ast.List{Type: astNonNull(astNamed("ID"))})),
+	})}
+	for _, fieldDef := range query.Fields {
+		delete(fields, fieldDef.Name.Value)
+	}
+	for _, name := range sortedKeys(fields) {
+		query.Fields = append(query.Fields, fields[name])
+	}
+}
+
+// typedValue is an object fetched by node, nodes or _entities together with its
+// type. The middleware of the fetched types unwraps it before their fields are
+// resolved.
+type typedValue struct {
+	typeName string
+	value    interface{}
+}
+
+// bindNodes sets the resolvers of node and nodes and the ResolveType function of Node
+// and registers the middlewares of the node types.
+func (g *Context) bindNodes() {
+	g.SetResolveType("Node", nil)
+
+	// The configs are set instead of using SetResolver, which would define the fields
+	// of Query and thereby ignore later changes of its config
+	if query, ok := g.objectConfigs["Query"]; ok {
+		fields := configFields(query.Fields)
+		if node, ok := fields["node"]; ok {
+			node.Resolve = func(p graphql.ResolveParams) (interface{}, error) {
+				id, _ := p.Args["id"].(string)
+				return g.fetchNode(p.Context, id)
+			}
+		}
+		if nodes, ok := fields["nodes"]; ok {
+			nodes.Resolve = func(p graphql.ResolveParams) (interface{}, error) {
+				ids, _ := p.Args["ids"].([]interface{})
+				nodes := make([]interface{}, len(ids))
+				for i, id := range ids {
+					node, err := g.fetchNode(p.Context, fmt.Sprint(id))
+					if err != nil {
+						return nil, err
+					}
+					nodes[i] = node
+				}
+				return nodes, nil
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(g.objectConfigs) {
+		if !g.isNodeType(name) {
+			continue
+		}
+		typeName := name
+		g.middlewares[typeName] = append(g.middlewares[typeName], unwrapTyped)
+		g.middlewares[typeName+".id"] = append(g.middlewares[typeName+".id"], func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+			return func(p graphql.ResolveParams) (interface{}, error) {
+				id, err := next(p)
+				if id == nil || err != nil {
+					return id, err
+				}
+				return ToGlobalID(typeName, fmt.Sprint(id)), nil
+			}
+		})
+	}
+}
+
+func (g *Context) fetchNode(ctx context.Context, globalID string) (interface{}, error) {
+	typeName, id, err := FromGlobalID(globalID)
+	if err != nil {
+		return nil, err
+	}
+	fetch, ok := g.nodeFetchers[typeName]
+	if !ok {
+		return nil, fmt.Errorf("No fetcher registered for node type %s.", typeName)
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	value, err := fetch(ctx, id)
+	if value == nil || err != nil {
+		return nil, err
+	}
+	return typedValue{typeName: typeName, value: value}, nil
+}
+
+// unwrapTyped passes the fetched object instead of the typedValue to the resolvers of
+// its type.
+func unwrapTyped(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		if typed, ok := p.Source.(typedValue); ok {
+			p.Source = typed.value
+		}
+		return next(p)
+	}
+}
+
+// resolveNodeType resolves the type of Node values as described by RelayNodes.
+func (g *Context) resolveNodeType(resolveType graphql.ResolveTypeFn) graphql.ResolveTypeFn {
+	resolveTyped := g.resolveTypedValue(resolveType)
+	return func(p graphql.ResolveTypeParams) *graphql.Object {
+		if ob := resolveTyped(p); ob != nil {
+			return ob
+		}
+		node, ok := p.Info.Schema.Type("Node").(*graphql.Interface)
+		if !ok {
+			return nil
+		}
+		possibleTypes := p.Info.Schema.PossibleTypes(node)
+		for _, ob := range possibleTypes {
+			if ob.IsTypeOf != nil && ob.IsTypeOf(graphql.IsTypeOfParams{Value: p.Value, Info: p.Info, Context: p.Context}) {
+				return ob
+			}
+		}
+		if value, ok := p.Value.(map[string]interface{}); ok {
+			if typeName, ok := value["__typename"].(string); ok {
+				for _, ob := range possibleTypes {
+					if ob.Name() == typeName {
+						return ob
+					}
+				}
+			}
+		}
+		if len(possibleTypes) == 1 {
+			return possibleTypes[0]
+		}
+		return nil
+	}
+}
+
+// resolveTypedValue resolves the type of objects fetched by node, nodes and _entities
+// and falls back to resolveType for other values.
+func (g *Context) resolveTypedValue(resolveType graphql.ResolveTypeFn) graphql.ResolveTypeFn {
+	return func(p graphql.ResolveTypeParams) *graphql.Object {
+		if typed, ok := p.Value.(typedValue); ok {
+			return g.objects[typed.typeName]
+		}
+		if resolveType != nil {
+			return resolveType(p)
+		}
+		return nil
+	}
+}
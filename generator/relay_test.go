@@ -0,0 +1,169 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestGlobalID(t *testing.T) {
+	typeName, id, err := FromGlobalID(ToGlobalID("User", "1:2"))
+	if err != nil || typeName != "User" || id != "1:2" {
+		t.Errorf("Unexpected decoded global ID: %s %s %v", typeName, id, err)
+	}
+	if _, _, err := FromGlobalID("invalid"); err == nil {
+		t.Errorf("Expected error for invalid global ID")
+	}
+}
+
+func TestRelayNodes(t *testing.T) {
+	gql := `
+type Query {
+	me: User
+}
+type User implements Node {
+	id: ID!
+	name: String
+}
+type Post implements Node {
+	id: ID!
+	title: String
+}`
+
+	ctx, err := New(RelayNodes()).Generate(gql)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	if errs := ctx.Errors(); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+	printed := PrintSchema(ctx)
+	for _, expected := range []string{
+		"interface Node {\n  id: ID!\n}",
+		"  node(id: ID!): Node\n  nodes(ids: [ID!]!): [Node]!\n",
+	} {
+		if !strings.Contains(printed, expected) {
+			t.Errorf("Expected printed schema to contain %q:\n%s", expected, printed)
+		}
+	}
+
+	if err := ctx.RegisterNode("Query", nil); err == nil {
+		t.Errorf("Expected error registering a type not implementing Node")
+	}
+	ctx.RegisterNode("User", func(ctx context.Context, id string) (interface{}, error) {
+		return map[string]interface{}{"id": id, "name": "User " + id}, nil
+	})
+	ctx.RegisterNode("Post", func(ctx context.Context, id string) (interface{}, error) {
+		return map[string]interface{}{"id": id, "title": "Post " + id}, nil
+	})
+	ctx.SetResolver("Query", "me", func(p graphql.ResolveParams) (interface{}, error) {
+		return map[string]interface{}{"id": "1", "name": "Me"}, nil
+	})
+	schema, err := CreateSchemaFromContext(ctx)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+
+	query := fmt.Sprintf(`{
+		me { id }
+		node(id: %q) { id ... on User { name } }
+		nodes(ids: [%q, %q]) { __typename ... on Post { title } }
+	}`, ToGlobalID("User", "2"), ToGlobalID("Post", "3"), ToGlobalID("User", "4"))
+	r := graphql.Do(graphql.Params{Schema: schema, RequestString: query})
+	result, _ := json.Marshal(r)
+	expected := fmt.Sprintf(`{"data":{"me":{"id":%q},"node":{"id":%q,"name":"User 2"},"nodes":[{"__typename":"Post","title":"Post 3"},{"__typename":"User"}]}}`,
+		ToGlobalID("User", "1"), ToGlobalID("User", "2"))
+	if string(result) != expected {
+		t.Errorf("Unexpected result:\n%s", result)
+	}
+}
+
+type relayPost struct {
+	ID    string
+	Title string
+}
+
+func TestRelayNodeResolveType(t *testing.T) {
+	gql := `
+type Query {
+	viewer: Node
+	latest: Node
+}
+type User implements Node {
+	id: ID!
+	name: String
+}
+type Post implements Node {
+	id: ID!
+	title: String
+}`
+
+	ctx, err := New(RelayNodes()).Generate(gql)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	ctx.SetResolver("Query", "viewer", func(p graphql.ResolveParams) (interface{}, error) {
+		return map[string]interface{}{"__typename": "User", "id": "1", "name": "Me"}, nil
+	})
+	ctx.SetResolver("Query", "latest", func(p graphql.ResolveParams) (interface{}, error) {
+		return relayPost{ID: "2", Title: "News"}, nil
+	})
+	ctx.Object("Post").IsTypeOf = func(p graphql.IsTypeOfParams) bool {
+		_, ok := p.Value.(relayPost)
+		return ok
+	}
+	ctx.SetResolver("Post", "title", func(p graphql.ResolveParams) (interface{}, error) {
+		return p.Source.(relayPost).Title, nil
+	})
+	ctx.SetResolver("Post", "id", func(p graphql.ResolveParams) (interface{}, error) {
+		return p.Source.(relayPost).ID, nil
+	})
+	schema, err := CreateSchemaFromContext(ctx)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+
+	r := graphql.Do(graphql.Params{Schema: schema, RequestString: `{
+		viewer { id ... on User { name } }
+		latest { __typename ... on Post { title } }
+	}`})
+	result, _ := json.Marshal(r)
+	expected := fmt.Sprintf(`{"data":{"latest":{"__typename":"Post","title":"News"},"viewer":{"id":%q,"name":"Me"}}}`, ToGlobalID("User", "1"))
+	if string(result) != expected {
+		t.Errorf("Unexpected result:\n%s", result)
+	}
+
+	ctx, err = New(RelayNodes()).Generate(`
+type Query {
+	viewer: Node
+}
+type User implements Node {
+	id: ID!
+}`)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	ctx.SetResolver("Query", "viewer", func(p graphql.ResolveParams) (interface{}, error) {
+		return map[string]interface{}{"id": "1"}, nil
+	})
+	schema, err = CreateSchemaFromContext(ctx)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	r = graphql.Do(graphql.Params{Schema: schema, RequestString: `{ viewer { __typename id } }`})
+	result, _ = json.Marshal(r)
+	expected = fmt.Sprintf(`{"data":{"viewer":{"__typename":"User","id":%q}}}`, ToGlobalID("User", "1"))
+	if string(result) != expected {
+		t.Errorf("Unexpected result for the only node type:\n%s", result)
+	}
+}
@@ -0,0 +1,167 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+// ReloaderConfig configures a Reloader.
+type ReloaderConfig struct {
+	// FS and Patterns are the schema files as given to GenerateFS.
+	FS       fs.FS
+	Patterns []string
+
+	// Generator generates the contexts. It defaults to a Generator without options.
+	Generator *Generator
+
+	// Bind binds the resolvers to a newly generated context before the schema is
+	// created from it.
+	Bind func(ctx *Context) error
+
+	// SchemaOptions are passed to CreateSchemaFromContext.
+	SchemaOptions []SchemaOption
+
+	// Interval is the interval Watch checks the files for changes in. It defaults
+	// to half a second.
+	Interval time.Duration
+
+	// OnReload is called with every schema swapped in, OnError with the errors of
+	// reloads by Watch.
+	OnReload func(schema graphql.Schema)
+	OnError  func(err error)
+}
+
+// Reloader serves the schema generated from files and swaps it atomically when the
+// files change. If a changed schema fails, the previous schema stays in place.
+type Reloader struct {
+	config ReloaderConfig
+	schema atomic.Pointer[graphql.Schema]
+
+	// mu serializes reloads, files holds the state of the files the last reload read.
+	mu    sync.Mutex
+	files *FileWatcher
+}
+
+// NewReloader returns a Reloader for the files of config. It has no schema until
+// the first successful call to Reload.
+func NewReloader(config ReloaderConfig) *Reloader {
+	if config.Generator == nil {
+		config.Generator = New()
+	}
+	if config.Interval == 0 {
+		config.Interval = 500 * time.Millisecond
+	}
+	return &Reloader{config: config, files: NewFileWatcher(config.FS, config.Patterns...)}
+}
+
+// Schema returns the current schema or nil if no schema was loaded yet. It is safe
+// to call concurrently with reloads.
+func (r *Reloader) Schema() *graphql.Schema {
+	return r.schema.Load()
+}
+
+// Reload generates a context from the files, binds it and swaps in the schema
+// created from it. On errors the previous schema stays in place.
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.files.Changed()
+	ctx, err := r.config.Generator.GenerateFS(r.config.FS, r.config.Patterns...)
+	if err != nil {
+		return err
+	}
+	if errs := ctx.Errors(); len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if r.config.Bind != nil {
+		if err := r.config.Bind(ctx); err != nil {
+			return err
+		}
+	}
+	schema, err := CreateSchemaFromContext(ctx, r.config.SchemaOptions...)
+	if err != nil {
+		return err
+	}
+
+	r.schema.Store(&schema)
+	if r.config.OnReload != nil {
+		r.config.OnReload(schema)
+	}
+	return nil
+}
+
+// Watch checks the files for changes since the last reload until ctx is done and
+// reloads the schema when they change. Errors are passed to OnError.
+func (r *Reloader) Watch(ctx context.Context) {
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		r.mu.Lock()
+		changed := r.files.Changed()
+		r.mu.Unlock()
+		if !changed {
+			continue
+		}
+		if err := r.Reload(); err != nil && r.config.OnError != nil {
+			r.config.OnError(err)
+		}
+	}
+}
+
+// FileWatcher detects changes of the files of a file system matching patterns by
+// their names, sizes and modification times. It is not safe for concurrent use.
+type FileWatcher struct {
+	fsys     fs.FS
+	patterns []string
+	seen     string
+}
+
+// NewFileWatcher returns a FileWatcher for the files of fsys matching any of the
+// patterns, which use the syntax of fs.Glob. Changes are reported relative to the
+// files at the time of the call.
+func NewFileWatcher(fsys fs.FS, patterns ...string) *FileWatcher {
+	w := &FileWatcher{fsys: fsys, patterns: patterns}
+	w.seen = w.snapshot()
+	return w
+}
+
+// Changed reports whether the files changed since the last call or, for the first
+// call, since the FileWatcher was created.
+func (w *FileWatcher) Changed() bool {
+	current := w.snapshot()
+	changed := current != w.seen
+	w.seen = current
+	return changed
+}
+
+// snapshot returns a string identifying the names, sizes and modification times of
+// the files matching the patterns.
+func (w *FileWatcher) snapshot() string {
+	var b strings.Builder
+	for _, pattern := range w.patterns {
+		matches, _ := fs.Glob(w.fsys, pattern)
+		for _, match := range matches {
+			info, err := fs.Stat(w.fsys, match)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(&b, "%s %d %d\n", match, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,112 @@
+package generator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+// writeFileAtomically writes a file by renaming a temporary file, so that watchers
+// never see it partially written.
+func writeFileAtomically(t *testing.T, name, content string) {
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".tmp-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tmp.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := tmp.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "schema.graphql")
+	writeFileAtomically(t, file, "type Query { hello: String }")
+
+	errs := make(chan error, 10)
+	reloads := make(chan graphql.Schema, 10)
+	reloader := NewReloader(ReloaderConfig{
+		FS:       os.DirFS(dir),
+		Patterns: []string{"*.graphql"},
+		Bind: func(ctx *Context) error {
+			return ctx.SetResolver("Query", "hello", func(p graphql.ResolveParams) (interface{}, error) {
+				return "world", nil
+			})
+		},
+		Interval: 10 * time.Millisecond,
+		OnReload: func(schema graphql.Schema) { reloads <- schema },
+		OnError:  func(err error) { errs <- err },
+	})
+	if reloader.Schema() != nil {
+		t.Error("Expected no schema before the first reload")
+	}
+	if err := reloader.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	<-reloads
+	result := graphql.Do(graphql.Params{Schema: *reloader.Schema(), RequestString: "{ hello }"})
+	if len(result.Errors) > 0 || result.Data.(map[string]interface{})["hello"] != "world" {
+		t.Errorf("Expected hello world, got %v", result)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go reloader.Watch(ctx)
+	first := reloader.Schema()
+
+	// Modification times may be coarse, the size tells the versions apart as well
+	writeFileAtomically(t, file, "type Query { hello: String, broken: Missing }")
+	select {
+	case err := <-errs:
+		if reloader.Schema() != first {
+			t.Errorf("Expected the previous schema to stay in place after %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected an error for the broken schema")
+	}
+
+	writeFileAtomically(t, file, "type Query { hello: String, count: Int }")
+	select {
+	case schema := <-reloads:
+		if schema.QueryType().Fields()["count"] == nil {
+			t.Error("Expected the reloaded schema to have the new field")
+		}
+		if reloader.Schema() == first {
+			t.Error("Expected the schema to be swapped")
+		}
+	case err := <-errs:
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the schema to be reloaded")
+	}
+}
+
+func TestFileWatcher(t *testing.T) {
+	fsys := fstest.MapFS{"a.graphql": {Data: []byte("type Query { a: Int }")}}
+	watcher := NewFileWatcher(fsys, "*.graphql")
+	if watcher.Changed() {
+		t.Error("Expected no change")
+	}
+	fsys["b.graphql"] = &fstest.MapFile{Data: []byte("type B { b: Int }")}
+	if !watcher.Changed() {
+		t.Error("Expected a change for the new file")
+	}
+	fsys["a.graphql"] = &fstest.MapFile{Data: []byte("type Query { a: Int }"), ModTime: time.Unix(1, 0)}
+	if !watcher.Changed() {
+		t.Error("Expected a change for the modified file")
+	}
+	if watcher.Changed() {
+		t.Error("Expected changes to be reported once")
+	}
+}
@@ -0,0 +1,214 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+)
+
+// resolverCodegen emits the resolver interfaces of the objects of a context and the
+// Bind function wiring implementations of them into the context.
+type resolverCodegen struct {
+	*modelCodegen
+}
+
+// resolvedTypeNames returns the objects which get a resolver interface. Subscription
+// fields are resolved by Subscribe functions and are therefore left out.
+func resolvedTypeNames(ctx *Context) []string {
+	var names []string
+	for _, name := range sortedKeys(ctx.objectConfigs) {
+		if name != "Subscription" && len(configFields(ctx.objectConfigs[name].Fields)) > 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// requiredTypeNames returns the objects whose resolvers Bind requires.
+func requiredTypeNames(ctx *Context) []string {
+	var names []string
+	for _, name := range resolvedTypeNames(ctx) {
+		if rootTypeNames[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func resolverName(typeName string) string {
+	return goName(typeName) + "Resolver"
+}
+
+// signature returns the parameters and results of the resolver method of a field.
+func (c *resolverCodegen) signature(typeName, fieldName string, field *graphql.Field) string {
+	params := "ctx context.Context"
+	if !rootTypeNames[typeName] {
+		params += ", obj *" + goName(typeName)
+	}
+	if len(field.Args) > 0 {
+		params += ", args " + argsTypeName(typeName, fieldName)
+	}
+	return fmt.Sprintf("(%s) (%s, error)", params, c.goTypeOf(field.Type))
+}
+
+func generateResolvers(ctx *Context, config CodegenConfig, file *goFile) {
+	c := &resolverCodegen{&modelCodegen{ctx: ctx, config: config, file: file}}
+	file.use("context")
+	file.use("github.com/graphql-go/graphql")
+	file.use(ImportPath)
+
+	for _, name := range resolvedTypeNames(ctx) {
+		c.file.printf("// %s resolves the fields of %s.\n", resolverName(name), name)
+		c.file.printf("type %s interface {\n", resolverName(name))
+		fields := configFields(ctx.objectConfigs[name].Fields)
+		for _, fieldName := range sortedKeys(fields) {
+			c.comment("\t", goName(fieldName), fields[fieldName].Description)
+			c.file.printf("\t%s%s\n", goName(fieldName), c.signature(name, fieldName, fields[fieldName]))
+		}
+		c.file.printf("}\n\n")
+	}
+
+	c.file.printf("// Resolvers holds the implementations of the resolver interfaces. The resolvers\n")
+	c.file.printf("// of the root types are required, the others replace the default resolvers of\n")
+	c.file.printf("// the fields of their type if they are given.\n")
+	c.file.printf("type Resolvers struct {\n")
+	for _, name := range resolvedTypeNames(ctx) {
+		c.file.printf("\t%s %s\n", goName(name), resolverName(name))
+	}
+	c.file.printf("}\n\n")
+
+	c.bind()
+
+	for _, name := range resolvedTypeNames(ctx) {
+		if rootTypeNames[name] {
+			continue
+		}
+		typeName := goName(name)
+		c.file.use("fmt")
+		c.file.printf("func source%s(source interface{}) (*%s, error) {\n", typeName, typeName)
+		c.file.printf("\tswitch obj := source.(type) {\n")
+		c.file.printf("\tcase *%s:\n\t\treturn obj, nil\n", typeName)
+		c.file.printf("\tcase %s:\n\t\treturn &obj, nil\n", typeName)
+		c.file.printf("\t}\n")
+		c.file.printf("\treturn nil, fmt.Errorf(\"Expected %s as source, got %%T.\", source)\n", name)
+		c.file.printf("}\n\n")
+	}
+}
+
+func (c *resolverCodegen) bind() {
+	c.file.printf("// Bind wires the resolvers into the fields of ctx and resolves the types of\n")
+	c.file.printf("// interfaces and unions by the models implementing them.\n")
+	c.file.printf("func Bind(ctx *generator.Context, r Resolvers) error {\n")
+	for _, name := range requiredTypeNames(c.ctx) {
+		c.file.use("errors")
+		c.file.printf("\tif r.%s == nil {\n", goName(name))
+		c.file.printf("\t\treturn errors.New(\"Bind: No %s resolver given.\")\n", name)
+		c.file.printf("\t}\n")
+	}
+	c.file.printf("\n\tvar err error\n")
+	c.file.printf("\tbind := func(typeName, fieldName string, resolve graphql.FieldResolveFn) {\n")
+	c.file.printf("\t\tif err == nil {\n\t\t\terr = ctx.SetResolver(typeName, fieldName, resolve)\n\t\t}\n")
+	c.file.printf("\t}\n")
+	c.file.printf("\tbindType := func(typeName string, resolveType graphql.ResolveTypeFn) {\n")
+	c.file.printf("\t\tif err == nil {\n\t\t\terr = ctx.SetResolveType(typeName, resolveType)\n\t\t}\n")
+	c.file.printf("\t}\n\n")
+
+	for _, name := range resolvedTypeNames(c.ctx) {
+		typeName := goName(name)
+		// The resolvers of root types are required and checked above
+		if !rootTypeNames[name] {
+			c.file.printf("\tif r.%s != nil {\n", typeName)
+		}
+		fields := configFields(c.ctx.objectConfigs[name].Fields)
+		for _, fieldName := range sortedKeys(fields) {
+			c.file.printf("\t\tbind(%q, %q, func(p graphql.ResolveParams) (interface{}, error) {\n", name, fieldName)
+			call := "p.Context"
+			if !rootTypeNames[name] {
+				c.file.printf("\t\t\tobj, err := source%s(p.Source)\n", typeName)
+				c.file.printf("\t\t\tif err != nil {\n\t\t\t\treturn nil, err\n\t\t\t}\n")
+				call += ", obj"
+			}
+			if len(fields[fieldName].Args) > 0 {
+				c.file.printf("\t\t\targs, err := Decode%s(p)\n", argsTypeName(name, fieldName))
+				c.file.printf("\t\t\tif err != nil {\n\t\t\t\treturn nil, err\n\t\t\t}\n")
+				call += ", args"
+			}
+			c.file.printf("\t\t\tresult, err := r.%s.%s(%s)\n", typeName, goName(fieldName), call)
+			c.file.printf("\t\t\treturn result, err\n")
+			c.file.printf("\t\t})\n")
+		}
+		if !rootTypeNames[name] {
+			c.file.printf("\t}\n")
+		}
+	}
+
+	abstractTypes := make(map[string][]string)
+	for _, name := range sortedKeys(c.ctx.objectConfigs) {
+		if ifaces, ok := c.ctx.objectConfigs[name].Interfaces.([]*graphql.Interface); ok {
+			for _, iface := range ifaces {
+				abstractTypes[iface.Name()] = append(abstractTypes[iface.Name()], name)
+			}
+		}
+	}
+	for unionName, config := range c.ctx.unionConfigs {
+		types, _ := config.Types.([]*graphql.Object)
+		abstractTypes[unionName] = nil
+		for _, ob := range types {
+			abstractTypes[unionName] = append(abstractTypes[unionName], ob.Name())
+		}
+	}
+	for _, abstractName := range sortedKeys(abstractTypes) {
+		c.file.printf("\tbindType(%q, func(p graphql.ResolveTypeParams) *graphql.Object {\n", abstractName)
+		c.file.printf("\t\tswitch p.Value.(type) {\n")
+		for _, name := range abstractTypes[abstractName] {
+			if rootTypeNames[name] {
+				continue
+			}
+			c.file.printf("\t\tcase %s, *%s:\n", goName(name), goName(name))
+			c.file.printf("\t\t\treturn ctx.Object(%q)\n", name)
+		}
+		c.file.printf("\t\t}\n")
+		c.file.printf("\t\treturn nil\n")
+		c.file.printf("\t})\n")
+	}
+
+	c.file.printf("\treturn err\n")
+	c.file.printf("}\n\n")
+}
+
+// CodegenStubs emits a Go file with stub implementations of the resolver interfaces of
+// the root types and a constructor returning them as Resolvers. Unlike the file
+// emitted by Codegen it is meant to be edited.
+func CodegenStubs(ctx *Context, config CodegenConfig) ([]byte, error) {
+	if config.Package == "" {
+		return nil, fmt.Errorf("CodegenStubs: No package name given.")
+	}
+
+	file := newGoFile(config.Package)
+	file.edited = true
+	c := &resolverCodegen{&modelCodegen{ctx: ctx, config: config, file: file}}
+	file.use("context")
+
+	for _, name := range requiredTypeNames(ctx) {
+		stubName := goName(name) + "Stub"
+		file.printf("// %s implements %s.\n", stubName, resolverName(name))
+		file.printf("type %s struct{}\n\n", stubName)
+		fields := configFields(ctx.objectConfigs[name].Fields)
+		for _, fieldName := range sortedKeys(fields) {
+			file.printf("func (%s) %s%s {\n", stubName, goName(fieldName), c.signature(name, fieldName, fields[fieldName]))
+			file.printf("\tpanic(\"not implemented: %s.%s\")\n", name, fieldName)
+			file.printf("}\n\n")
+		}
+	}
+
+	file.printf("// NewResolvers returns the stub resolvers.\n")
+	file.printf("func NewResolvers() Resolvers {\n")
+	file.printf("\treturn Resolvers{\n")
+	for _, name := range requiredTypeNames(ctx) {
+		file.printf("\t\t%s: %sStub{},\n", goName(name), goName(name))
+	}
+	file.printf("\t}\n")
+	file.printf("}\n")
+
+	return file.bytes()
+}
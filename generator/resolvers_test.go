@@ -0,0 +1,99 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestSetResolverAndResolveType(t *testing.T) {
+	gql := `
+type Query {
+	search: [Result]
+}
+type Hello {
+	world: String
+}
+union Result = Hello`
+
+	ctx, err := Generate(gql)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	err = ctx.SetResolver("Query", "search", func(p graphql.ResolveParams) (interface{}, error) {
+		return []interface{}{map[string]interface{}{"world": "!"}}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ctx.SetResolveType("Result", func(p graphql.ResolveTypeParams) *graphql.Object {
+		return ctx.Object("Hello")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.SetResolver("Query", "unknown", nil); err == nil {
+		t.Errorf("Expected error for unknown field")
+	}
+
+	schema, err := CreateSchemaFromContext(ctx)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	r := graphql.Do(graphql.Params{Schema: schema, RequestString: `{ search { ... on Hello { world } } }`})
+	if len(r.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", r.Errors)
+	}
+	if fmt.Sprint(r.Data) != "map[search:[map[world:!]]]" {
+		t.Errorf("Unexpected result: %v", r.Data)
+	}
+}
+
+func TestCodegenResolvers(t *testing.T) {
+	gql := `
+type Query {
+	user(id: ID!): User
+}
+type User {
+	name: String
+}`
+
+	ctx, err := Generate(gql)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	code, err := Codegen(ctx, CodegenConfig{Package: "api", Resolvers: true})
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	for _, expected := range []string{
+		"type QueryResolver interface {\n\tUser(ctx context.Context, args QueryUserArgs) (*User, error)\n}",
+		"type UserResolver interface {\n\tName(ctx context.Context, obj *User) (*string, error)\n}",
+		"type Resolvers struct {\n\tQuery QueryResolver\n\tUser  UserResolver\n}",
+		"func Bind(ctx *generator.Context, r Resolvers) error {",
+		"bind(\"Query\", \"user\", func(p graphql.ResolveParams) (interface{}, error) {",
+		"func sourceUser(source interface{}) (*User, error) {",
+	} {
+		if !strings.Contains(string(code), expected) {
+			t.Errorf("Expected generated code to contain %q:\n%s", expected, code)
+		}
+	}
+
+	stubs, err := CodegenStubs(ctx, CodegenConfig{Package: "api"})
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	if strings.Contains(string(stubs), "DO NOT EDIT") {
+		t.Errorf("Expected stubs to be editable")
+	}
+	if !strings.Contains(string(stubs), "func (QueryStub) User(ctx context.Context, args QueryUserArgs) (*User, error) {") {
+		t.Errorf("Expected stub for Query.user:\n%s", stubs)
+	}
+}
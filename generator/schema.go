@@ -5,12 +5,62 @@ import (
 	"github.com/graphql-go/graphql"
 )
 
-func CreateSchemaFromContext(ctx *Context) (graphql.Schema, error) {
+// SchemaOption configures CreateSchemaFromContext.
+type SchemaOption func(*schemaOptions)
+
+type schemaOptions struct {
+	resolvers *resolverCheck
+}
+
+// Strict makes CreateSchemaFromContext fail if fields of the root types, or of all
+// types with AllTypes, lack a resolve function.
+func Strict(options ...ResolverOption) SchemaOption {
+	return func(opts *schemaOptions) {
+		opts.resolvers = newResolverCheck(options)
+	}
+}
+
+func CreateSchemaFromContext(ctx *Context, options ...SchemaOption) (graphql.Schema, error) {
+	opts := &schemaOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+
 	if query, ok := ctx.objects["Query"]; ok {
-		return graphql.NewSchema(graphql.SchemaConfig {
-			Query: query,
+		schema, err := graphql.NewSchema(graphql.SchemaConfig {
+			Query:        query,
+			Mutation:     ctx.objects["Mutation"],
+			Subscription: ctx.objects["Subscription"],
+			Types:        ctx.schemaTypes(),
+			Directives:   ctx.schemaDirectives(),
 		})
+		if err != nil {
+			return schema, err
+		}
+		if opts.resolvers != nil {
+			if err := checkResolvers(ctx, schema, opts.resolvers); err != nil {
+				return graphql.Schema{}, err
+			}
+		}
+		ctx.wrapResolvers()
+		return schema, nil
 	} else {
 		return graphql.Schema{}, errors.New("Your context does not define a Query root type!")
 	}
 }
+
+// schemaTypes returns the objects, interfaces and unions of the context, so that
+// objects only reachable as implementations of interfaces are part of the schema.
+func (g *Context) schemaTypes() []graphql.Type {
+	var types []graphql.Type
+	for _, name := range sortedKeys(g.objects) {
+		types = append(types, g.objects[name])
+	}
+	for _, name := range sortedKeys(g.interfaces) {
+		types = append(types, g.interfaces[name])
+	}
+	for _, name := range sortedKeys(g.unions) {
+		types = append(types, g.unions[name])
+	}
+	return types
+}
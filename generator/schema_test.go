@@ -27,3 +27,25 @@ type Query {
 	}
 }
 
+
+func TestIntrospect(t *testing.T) {
+	gql := `
+type Query {
+	a: String
+}`
+
+	ctx, errp := Generate(gql)
+	if errp != nil {
+		fmt.Print(errp)
+		t.FailNow()
+	}
+	result, err := Introspect(ctx)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	schema := result.Data.(map[string]interface{})["__schema"].(map[string]interface{})
+	if schema["queryType"].(map[string]interface{})["name"] != "Query" {
+		t.Errorf("Unexpected query type in introspection: %v", schema["queryType"])
+	}
+}
@@ -0,0 +1,146 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// DefaultResolverDirective marks fields or object types whose fields intentionally
+// use the default resolver, e.g. `name: String @defaultResolver`.
+const DefaultResolverDirective = "defaultResolver"
+
+// UnresolvedField is a field of an object without a resolve function.
+type UnresolvedField struct {
+	Type  string
+	Field string
+}
+
+func (f UnresolvedField) String() string {
+	return f.Type + "." + f.Field
+}
+
+// ResolverOption configures which fields UnresolvedFields and Strict check.
+type ResolverOption func(*resolverCheck)
+
+type resolverCheck struct {
+	allTypes bool
+	allowed  map[string]bool
+}
+
+// AllTypes checks the fields of all objects instead of only those of the root types.
+func AllTypes() ResolverOption {
+	return func(check *resolverCheck) {
+		check.allTypes = true
+	}
+}
+
+// Allow excludes fields, given as Type.field, or all fields of types, given as Type,
+// from the check.
+func Allow(fields ...string) ResolverOption {
+	return func(check *resolverCheck) {
+		for _, field := range fields {
+			check.allowed[field] = true
+		}
+	}
+}
+
+func newResolverCheck(options []ResolverOption) *resolverCheck {
+	check := &resolverCheck{allowed: make(map[string]bool)}
+	for _, option := range options {
+		option(check)
+	}
+	return check
+}
+
+// UnresolvedFields lists the fields of the root types lacking a resolve function,
+// sorted by type and field name. Subscription fields count as resolved if they have
+// a Subscribe function. Fields and types marked with @defaultResolver are left out.
+func (g *Context) UnresolvedFields(options ...ResolverOption) []UnresolvedField {
+	return g.unresolvedFields(newResolverCheck(options), func(typeName, fieldName string) bool {
+		field := configFields(g.objectConfigs[typeName].Fields)[fieldName]
+		return field.Resolve != nil || field.Subscribe != nil
+	})
+}
+
+// unresolvedFields lists the checked fields for which resolved returns false.
+func (g *Context) unresolvedFields(check *resolverCheck, resolved func(typeName, fieldName string) bool) []UnresolvedField {
+	g.defaultResolverFields(check.allowed)
+
+	var unresolved []UnresolvedField
+	for _, typeName := range sortedKeys(g.objectConfigs) {
+		if !check.allTypes && !rootTypeNames[typeName] || check.allowed[typeName] {
+			continue
+		}
+		for _, fieldName := range sortedKeys(configFields(g.objectConfigs[typeName].Fields)) {
+			field := UnresolvedField{Type: typeName, Field: fieldName}
+			if !check.allowed[field.String()] && !resolved(typeName, fieldName) {
+				unresolved = append(unresolved, field)
+			}
+		}
+	}
+	return unresolved
+}
+
+// defaultResolverFields adds the types and fields marked with @defaultResolver to allowed.
+func (g *Context) defaultResolverFields(allowed map[string]bool) {
+	if g.document == nil {
+		return
+	}
+	for _, def := range g.document.Definitions {
+		var obdef *ast.ObjectDefinition
+		switch def.(type) {
+		case *ast.ObjectDefinition:
+			obdef = def.(*ast.ObjectDefinition)
+		case *ast.TypeExtensionDefinition:
+			obdef = def.(*ast.TypeExtensionDefinition).Definition
+		default:
+			continue
+		}
+		if hasDirective(obdef.Directives, DefaultResolverDirective) {
+			allowed[obdef.Name.Value] = true
+		}
+		for _, fieldDef := range obdef.Fields {
+			if hasDirective(fieldDef.Directives, DefaultResolverDirective) {
+				allowed[obdef.Name.Value+"."+fieldDef.Name.Value] = true
+			}
+		}
+	}
+}
+
+func hasDirective(directives []*ast.Directive, name string) bool {
+	for _, directive := range directives {
+		if directive.Name.Value == name {
+			return true
+		}
+	}
+	return false
+}
+
+// checkResolvers returns an error listing the fields of schema lacking a resolve function.
+func checkResolvers(ctx *Context, schema graphql.Schema, check *resolverCheck) error {
+	unresolved := ctx.unresolvedFields(check, func(typeName, fieldName string) bool {
+		object, ok := schema.Type(typeName).(*graphql.Object)
+		if !ok {
+			return true
+		}
+		field, ok := object.Fields()[fieldName]
+		if !ok {
+			return true
+		}
+		if resolve, wrapped := ctx.resolvers[field]; wrapped {
+			return resolve != nil || field.Subscribe != nil
+		}
+		return field.Resolve != nil || field.Subscribe != nil
+	})
+	if len(unresolved) == 0 {
+		return nil
+	}
+	names := make([]string, len(unresolved))
+	for i, field := range unresolved {
+		names[i] = field.String()
+	}
+	return fmt.Errorf("Fields without resolvers: %s", strings.Join(names, ", "))
+}
@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestUnresolvedFields(t *testing.T) {
+	gql := `
+type Query {
+	a: String
+	b: String
+	health: String @defaultResolver
+	user: User
+}
+type User @defaultResolver {
+	name: String
+}
+type Post {
+	title: String
+}`
+
+	ctx, err := Generate(gql)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	resolve := func(p graphql.ResolveParams) (interface{}, error) { return nil, nil }
+	ctx.Extend("Query", UpdateObjectFn(func(config graphql.ObjectConfig) graphql.ObjectConfig {
+		config.Fields.(graphql.Fields)["a"].Resolve = resolve
+		return config
+	}))
+
+	if unresolved := fmt.Sprint(ctx.UnresolvedFields()); unresolved != "[Query.b Query.user]" {
+		t.Errorf("Unexpected unresolved fields: %s", unresolved)
+	}
+	if unresolved := fmt.Sprint(ctx.UnresolvedFields(AllTypes(), Allow("Query.b"))); unresolved != "[Post.title Query.user]" {
+		t.Errorf("Unexpected unresolved fields of all types: %s", unresolved)
+	}
+
+	_, err = CreateSchemaFromContext(ctx, Strict())
+	if err == nil || err.Error() != "Fields without resolvers: Query.b, Query.user" {
+		t.Errorf("Unexpected strict mode error: %v", err)
+	}
+	if _, err := CreateSchemaFromContext(ctx); err != nil {
+		t.Errorf("Unexpected error without strict mode: %s", err)
+	}
+
+	ctx.SetResolver("Query", "b", resolve)
+	ctx.SetResolver("Query", "user", resolve)
+	if _, err := CreateSchemaFromContext(ctx, Strict()); err != nil {
+		t.Errorf("Unexpected strict mode error: %s", err)
+	}
+}
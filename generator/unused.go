@@ -0,0 +1,122 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// UnreachableTypes returns the names of the types which are not reachable from the
+// operation types through fields, arguments, input fields, interface implementations
+// or union members in alphabetical order. Types used by the arguments of directives
+// are reachable.
+func (g *Context) UnreachableTypes() []string {
+	reachable := make(map[string]bool)
+	var visit func(name string)
+	visit = func(name string) {
+		if _, ok := g.GetObject(name); !ok || reachable[name] {
+			return
+		}
+		reachable[name] = true
+		for _, ref := range g.typeReferences(name) {
+			visit(ref)
+		}
+	}
+	for name := range rootTypeNames {
+		visit(name)
+	}
+	for _, config := range g.directiveConfigs {
+		for _, arg := range config.Args {
+			visit(namedType(arg.Type).Name())
+		}
+	}
+
+	var unreachable []string
+	for _, name := range g.TypeNames() {
+		if !reachable[name] {
+			unreachable = append(unreachable, name)
+		}
+	}
+	return unreachable
+}
+
+// typeReferences returns the names of the types used by a type. The implementations
+// of an interface count as used by it.
+func (g *Context) typeReferences(name string) []string {
+	var refs []string
+	addFields := func(fields graphql.Fields) {
+		for _, field := range fields {
+			refs = append(refs, namedType(field.Type).Name())
+			for _, arg := range field.Args {
+				refs = append(refs, namedType(arg.Type).Name())
+			}
+		}
+	}
+	if config, ok := g.objectConfigs[name]; ok {
+		ifaces, _ := config.Interfaces.([]*graphql.Interface)
+		for _, iface := range ifaces {
+			refs = append(refs, iface.Name())
+		}
+		addFields(configFields(config.Fields))
+	}
+	if config, ok := g.interfaceConfigs[name]; ok {
+		addFields(configFields(config.Fields))
+		for objectName, config := range g.objectConfigs {
+			ifaces, _ := config.Interfaces.([]*graphql.Interface)
+			for _, iface := range ifaces {
+				if iface.Name() == name {
+					refs = append(refs, objectName)
+				}
+			}
+		}
+	}
+	if config, ok := g.unionConfigs[name]; ok {
+		types, _ := config.Types.([]*graphql.Object)
+		for _, ob := range types {
+			refs = append(refs, ob.Name())
+		}
+	}
+	if config, ok := g.inputConfigs[name]; ok {
+		fields, _ := config.Fields.(graphql.InputObjectConfigFieldMap)
+		for _, field := range fields {
+			refs = append(refs, namedType(field.Type).Name())
+		}
+	}
+	return refs
+}
+
+// Prune removes the types returned by UnreachableTypes from the context together
+// with their middlewares and constraints, and returns their names.
+func (g *Context) Prune() []string {
+	unreachable := g.UnreachableTypes()
+	for _, name := range unreachable {
+		delete(g.objects, name)
+		delete(g.interfaces, name)
+		delete(g.enums, name)
+		delete(g.unions, name)
+		delete(g.scalars, name)
+		delete(g.inputs, name)
+		delete(g.objectConfigs, name)
+		delete(g.interfaceConfigs, name)
+		delete(g.enumConfigs, name)
+		delete(g.unionConfigs, name)
+		delete(g.scalarConfigs, name)
+		delete(g.inputConfigs, name)
+		delete(g.nodeFetchers, name)
+		delete(g.entities, name)
+		delete(g.referenceResolvers, name)
+		for _, keys := range []map[string][]FieldMiddleware{g.middlewares, g.pending} {
+			for key := range keys {
+				if key == name || strings.HasPrefix(key, name+".") {
+					delete(keys, key)
+				}
+			}
+		}
+		for key := range g.constraints {
+			if strings.HasPrefix(key, name+".") {
+				delete(g.constraints, key)
+			}
+		}
+	}
+	return unreachable
+}
@@ -0,0 +1,81 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestUnreachableTypes(t *testing.T) {
+	ctx, err := Generate(`
+directive @auth(role: Role) on FIELD_DEFINITION
+type Query {
+	node(id: ID!): Node
+	search(filter: Filter): [Result]
+}
+interface Node {
+	id: ID!
+}
+type User implements Node {
+	id: ID!
+	address: Address
+}
+type Address {
+	city: String
+}
+union Result = Post
+type Post {
+	title: String
+}
+input Filter {
+	after: Date
+}
+scalar Date
+enum Role {
+	ADMIN
+}
+type Legacy {
+	old: OldEnum
+}
+enum OldEnum {
+	A
+}
+input OldInput {
+	a: String
+}
+interface Orphan {
+	a: String
+}`)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	ctx.UpdateObject("Date", graphql.ScalarConfig{Name: "Date", Serialize: func(value interface{}) interface{} { return value }})
+	ctx.middlewares["Legacy.old"] = []FieldMiddleware{func(next graphql.FieldResolveFn) graphql.FieldResolveFn { return next }}
+
+	expected := []string{"Legacy", "OldEnum", "OldInput", "Orphan"}
+	if unreachable := ctx.UnreachableTypes(); !reflect.DeepEqual(unreachable, expected) {
+		t.Errorf("Expected unreachable types %v, got %v", expected, unreachable)
+	}
+
+	if pruned := ctx.Prune(); !reflect.DeepEqual(pruned, expected) {
+		t.Errorf("Expected pruned types %v, got %v", expected, pruned)
+	}
+	if unreachable := ctx.UnreachableTypes(); len(unreachable) > 0 {
+		t.Errorf("Expected no unreachable types after pruning, got %v", unreachable)
+	}
+	if _, ok := ctx.GetObject("Legacy"); ok {
+		t.Error("Expected Legacy to be pruned")
+	}
+	if _, ok := ctx.middlewares["Legacy.old"]; ok {
+		t.Error("Expected the middlewares of Legacy to be pruned")
+	}
+	resolveType := func(p graphql.ResolveTypeParams) *graphql.Object { return nil }
+	ctx.SetResolveType("Node", resolveType)
+	ctx.SetResolveType("Result", resolveType)
+	if _, err := CreateSchemaFromContext(ctx); err != nil {
+		t.Errorf("Expected a valid schema after pruning, got %s", err)
+	}
+}
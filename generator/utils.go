@@ -1,6 +1,8 @@
 package generator
 
 import (
+	"sort"
+
 	"github.com/graphql-go/graphql/language/ast"
 )
 
@@ -12,3 +14,12 @@ func mapSlice(b []ast.Value, fn mapFunc) (newSlice []interface{}) {
 	}
 	return
 }
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,163 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/location"
+)
+
+// SchemaError is an error found while generating or validating a schema. It
+// points to the definition it originates from if the definition is known.
+type SchemaError struct {
+	Message string
+	Source  string
+	Line    int
+	Column  int
+}
+
+func (e *SchemaError) Error() string {
+	if e.Line == 0 {
+		return e.Message
+	}
+	if e.Source == "" {
+		return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", e.Source, e.Line, e.Column, e.Message)
+}
+
+// defaultSourceName is the name the parser gives to sources without a name.
+const defaultSourceName = "GraphQL"
+
+func newSchemaError(loc *ast.Location, err error) *SchemaError {
+	schemaErr := &SchemaError{Message: err.Error()}
+	if loc != nil && loc.Source != nil {
+		sourceLoc := location.GetLocation(loc.Source, loc.Start)
+		if loc.Source.Name != defaultSourceName {
+			schemaErr.Source = loc.Source.Name
+		}
+		schemaErr.Line = sourceLoc.Line
+		schemaErr.Column = sourceLoc.Column
+	}
+	return schemaErr
+}
+
+// Location returns the location of the definition with the given name. Directive
+// definitions are named with a leading @.
+func (g *Context) Location(which string) *ast.Location {
+	if g.document == nil {
+		return nil
+	}
+	for _, def := range g.document.Definitions {
+		if _, ok := def.(*ast.TypeExtensionDefinition); ok {
+			continue
+		}
+		if definitionName(def) == which {
+			return def.GetLoc()
+		}
+	}
+	return nil
+}
+
+func definitionName(def ast.Node) string {
+	switch def.(type) {
+	case *ast.ObjectDefinition:
+		return def.(*ast.ObjectDefinition).Name.Value
+	case *ast.InterfaceDefinition:
+		return def.(*ast.InterfaceDefinition).Name.Value
+	case *ast.EnumDefinition:
+		return def.(*ast.EnumDefinition).Name.Value
+	case *ast.ScalarDefinition:
+		return def.(*ast.ScalarDefinition).Name.Value
+	case *ast.UnionDefinition:
+		return def.(*ast.UnionDefinition).Name.Value
+	case *ast.InputObjectDefinition:
+		return def.(*ast.InputObjectDefinition).Name.Value
+	case *ast.TypeExtensionDefinition:
+		return def.(*ast.TypeExtensionDefinition).Definition.Name.Value
+	case *ast.DirectiveDefinition:
+		return "@" + def.(*ast.DirectiveDefinition).Name.Value
+	}
+	return ""
+}
+
+// typeErrors collects the errors graphql-go recorded while building the types of the context.
+func (g *Context) typeErrors() map[string]error {
+	errs := make(map[string]error)
+	for name, t := range g.objects {
+		t.Fields()
+		if t.Error() != nil {
+			errs[name] = t.Error()
+		}
+	}
+	for name, t := range g.interfaces {
+		t.Fields()
+		if t.Error() != nil {
+			errs[name] = t.Error()
+		}
+	}
+	for name, t := range g.unions {
+		if t.Error() != nil {
+			errs[name] = t.Error()
+		}
+	}
+	for name, t := range g.enums {
+		if t.Error() != nil {
+			errs[name] = t.Error()
+		}
+	}
+	for name, t := range g.scalars {
+		if t.Error() != nil {
+			errs[name] = t.Error()
+		}
+	}
+	for name, t := range g.inputs {
+		t.Fields()
+		if t.Error() != nil {
+			errs[name] = t.Error()
+		}
+	}
+	return errs
+}
+
+// Errors reports the definitions which could not be generated. It returns nil if
+// every definition was generated.
+func (g *Context) Errors() []error {
+	var errs []error
+
+	var failed []int
+	for astIndex := range g.failures {
+		failed = append(failed, astIndex)
+	}
+	sort.Ints(failed)
+	for _, astIndex := range failed {
+		def := g.document.Definitions[astIndex]
+		errs = append(errs, newSchemaError(def.GetLoc(), g.failures[astIndex]))
+	}
+	return errs
+}
+
+// Validate reports the errors of the context, the types graphql-go rejected and, if
+// there are none, the error of creating a schema from the context. It returns nil if
+// the context is valid.
+func (g *Context) Validate() []error {
+	errs := g.Errors()
+
+	typeErrs := g.typeErrors()
+	for astIndex := range g.failures {
+		// The types of failed definitions are reported by Errors already
+		delete(typeErrs, definitionName(g.document.Definitions[astIndex]))
+	}
+	for _, name := range sortedKeys(typeErrs) {
+		errs = append(errs, newSchemaError(g.Location(name), fmt.Errorf("%s: %s", name, typeErrs[name])))
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if _, err := CreateSchemaFromContext(g); err != nil {
+		return []error{&SchemaError{Message: err.Error()}}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestValidateValidSchema(t *testing.T) {
+	gql := `
+type Query {
+	hello: String
+}`
+
+	ctx, err := Generate(gql)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	if errs := ctx.Validate(); errs != nil {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+}
+
+func TestValidateReportsUnknownType(t *testing.T) {
+	gql := `
+type Query {
+	hello: World
+}`
+
+	ctx, err := Generate(gql)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	errs := ctx.Validate()
+	if len(errs) != 1 {
+		t.Fatalf("Expected one error, got %v", errs)
+	}
+	expected := "2:1: Could not map type World: Type not found!"
+	if errs[0].Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, errs[0].Error())
+	}
+}
+
+func TestValidateReportsMissingQuery(t *testing.T) {
+	gql := `
+type Hello {
+	world: String
+}`
+
+	ctx, err := Generate(gql)
+	if err != nil {
+		fmt.Print(err)
+		t.FailNow()
+	}
+	if errs := ctx.Validate(); len(errs) != 1 {
+		t.Errorf("Expected one error, got %v", errs)
+	}
+}
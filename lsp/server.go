@@ -0,0 +1,511 @@
+// Package lsp implements a language server for GraphQL SDL files. It reports the
+// errors of the schema generated from all files of the workspace and types defined
+// more than once as diagnostics and provides go to definition and hover for type
+// and directive references and completion of type names.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/alpox/graphql-go-gen/generator"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// sdlExtensions are the extensions of the files loaded from the workspace.
+var sdlExtensions = []string{".graphql", ".graphqls", ".gql"}
+
+// builtinScalars are completed in addition to the types of the schema.
+var builtinScalars = []*graphql.Scalar{graphql.Int, graphql.Float, graphql.String, graphql.Boolean, graphql.ID}
+
+// Server is a language server communicating over a reader and a writer, e.g.
+// stdin and stdout.
+type Server struct {
+	in  *bufio.Reader
+	out io.Writer
+
+	// files are the SDL files of the workspace by URI. The content of files opened in
+	// the editor overrides the content on disk.
+	files map[string]string
+
+	// ctx is the context generated from the files without syntax errors.
+	ctx *generator.Context
+
+	// published are the files diagnostics were published for.
+	published map[string]bool
+	shutdown  bool
+}
+
+// NewServer returns a server reading requests from in and writing responses to out.
+func NewServer(in io.Reader, out io.Writer) *Server {
+	return &Server{
+		in:        bufio.NewReader(in),
+		out:       out,
+		files:     make(map[string]string),
+		published: make(map[string]bool),
+	}
+}
+
+// Run serves requests until the exit notification or the end of the input.
+func (s *Server) Run() error {
+	for {
+		body, err := readMessage(s.in)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			if err := s.respond(json.RawMessage("null"), nil, &responseError{codeParseError, err.Error()}); err != nil {
+				return err
+			}
+			continue
+		}
+		if req.Method == "exit" {
+			if !s.shutdown {
+				return errors.New("exit before shutdown")
+			}
+			return nil
+		}
+		if err := s.handle(req); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(req request) error {
+	if req.ID == nil {
+		return s.handleNotification(req)
+	}
+	result, respErr := s.call(req)
+	return s.respond(req.ID, result, respErr)
+}
+
+// call answers a request.
+func (s *Server) call(req request) (interface{}, *responseError) {
+	var params textDocumentPositionParams
+	switch req.Method {
+	case "initialize":
+		var params initializeParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &responseError{codeInvalidParams, err.Error()}
+		}
+		result, err := s.initialize(params)
+		if err != nil {
+			return nil, &responseError{codeInternalError, err.Error()}
+		}
+		return result, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/completion":
+		return s.completion(), nil
+	case "textDocument/definition", "textDocument/hover":
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &responseError{codeInvalidParams, err.Error()}
+		}
+	default:
+		return nil, &responseError{codeMethodNotFound, "Method not found: " + req.Method}
+	}
+	if req.Method == "textDocument/definition" {
+		return s.definition(params), nil
+	}
+	return s.hover(params), nil
+}
+
+// handleNotification handles a notification. Notifications the server does not
+// handle and notifications with invalid parameters are ignored.
+func (s *Server) handleNotification(req request) error {
+	switch req.Method {
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if json.Unmarshal(req.Params, &params) != nil {
+			return nil
+		}
+		s.files[params.TextDocument.URI] = params.TextDocument.Text
+	case "textDocument/didChange":
+		var params didChangeParams
+		if json.Unmarshal(req.Params, &params) != nil || len(params.ContentChanges) == 0 {
+			return nil
+		}
+		s.files[params.TextDocument.URI] = params.ContentChanges[len(params.ContentChanges)-1].Text
+	case "textDocument/didClose":
+		var params didCloseParams
+		if json.Unmarshal(req.Params, &params) != nil {
+			return nil
+		}
+		uri := params.TextDocument.URI
+		// Closed files stay part of the schema as long as they exist on disk
+		if text, err := os.ReadFile(uriPath(uri)); err == nil {
+			s.files[uri] = string(text)
+		} else {
+			delete(s.files, uri)
+		}
+	default:
+		return nil
+	}
+	return s.analyze()
+}
+
+func (s *Server) respond(id json.RawMessage, result interface{}, respErr *responseError) error {
+	resp := response{JSONRPC: "2.0", ID: id, Error: respErr}
+	if respErr == nil {
+		marshaled, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		resp.Result = marshaled
+	}
+	return writeMessage(s.out, resp)
+}
+
+func (s *Server) notify(method string, params interface{}) error {
+	return writeMessage(s.out, notification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (s *Server) initialize(params initializeParams) (interface{}, error) {
+	if root := uriPath(params.RootURI); root != "" {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || !hasExtension(path) {
+				return err
+			}
+			text, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			s.files[pathURI(path)] = string(text)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return map[string]interface{}{
+		"capabilities": map[string]interface{}{
+			"textDocumentSync":   1,
+			"definitionProvider": true,
+			"hoverProvider":      true,
+			"completionProvider": map[string]interface{}{},
+		},
+		"serverInfo": map[string]string{"name": "graphql-go-gen"},
+	}, nil
+}
+
+func hasExtension(path string) bool {
+	for _, ext := range sdlExtensions {
+		if filepath.Ext(path) == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// uriPath returns the path of a file URI or an empty string for other URIs.
+func uriPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return ""
+	}
+	return filepath.FromSlash(u.Path)
+}
+
+func pathURI(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+// analyze generates the context from all files and publishes the syntax errors of
+// the files and the errors of the context as diagnostics.
+func (s *Server) analyze() error {
+	diagnostics := make(map[string][]diagnostic)
+	var sources []*source.Source
+	definitions := make(map[string][]*ast.Name)
+	uris := make([]string, 0, len(s.files))
+	for uri := range s.files {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+	for _, uri := range uris {
+		src := source.NewSource(&source.Source{Body: []byte(s.files[uri]), Name: uri})
+		doc, err := parser.Parse(parser.ParseParams{Source: src})
+		if err != nil {
+			diagnostics[uri] = append(diagnostics[uri], syntaxDiagnostic(s.files[uri], err))
+			continue
+		}
+		for _, def := range doc.Definitions {
+			if name, display := definitionName(def); name != nil {
+				definitions[display] = append(definitions[display], name)
+			}
+		}
+		sources = append(sources, src)
+	}
+
+	// The schema of conflicting definitions is not generated, the conflicts are
+	// reported at each definition instead
+	conflicts := false
+	displays := make([]string, 0, len(definitions))
+	for display := range definitions {
+		displays = append(displays, display)
+	}
+	sort.Strings(displays)
+	for _, display := range displays {
+		names := definitions[display]
+		if len(names) < 2 {
+			continue
+		}
+		conflicts = true
+		for _, name := range names {
+			uri := name.Loc.Source.Name
+			diagnostics[uri] = append(diagnostics[uri], diagnostic{
+				Range:    locationOf(name.Loc).Range,
+				Severity: severityError,
+				Source:   "graphql-go-gen",
+				Message:  fmt.Sprintf("%s is defined %d times.", display, len(names)),
+			})
+		}
+	}
+
+	s.ctx = nil
+	if len(sources) > 0 && !conflicts {
+		ctx, err := generator.GenerateSources(sources...)
+		if err != nil {
+			return err
+		}
+		s.ctx = ctx
+		for _, err := range ctx.Validate() {
+			var schemaErr *generator.SchemaError
+			if !errors.As(err, &schemaErr) {
+				continue
+			}
+			uri := schemaErr.Source
+			if _, ok := s.files[uri]; !ok {
+				// Errors of the whole schema are reported at the start of the first file
+				uri = sources[0].Name
+			}
+			diagnostics[uri] = append(diagnostics[uri], diagnostic{
+				Range:    wordRange(s.files[uri], schemaErr.Line-1, schemaErr.Column-1),
+				Severity: severityError,
+				Source:   "graphql-go-gen",
+				Message:  schemaErr.Message,
+			})
+		}
+	}
+
+	for _, uri := range uris {
+		if len(diagnostics[uri]) == 0 && !s.published[uri] {
+			continue
+		}
+		if diagnostics[uri] == nil {
+			diagnostics[uri] = []diagnostic{}
+		}
+		if err := s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{uri, diagnostics[uri]}); err != nil {
+			return err
+		}
+		s.published[uri] = len(diagnostics[uri]) > 0
+	}
+	return nil
+}
+
+// definitionName returns the name of a definition and how it is referred to, with
+// a leading @ for directives. Extensions have no name of their own.
+func definitionName(def ast.Node) (*ast.Name, string) {
+	var name *ast.Name
+	switch def := def.(type) {
+	case *ast.ObjectDefinition:
+		name = def.Name
+	case *ast.InterfaceDefinition:
+		name = def.Name
+	case *ast.UnionDefinition:
+		name = def.Name
+	case *ast.EnumDefinition:
+		name = def.Name
+	case *ast.ScalarDefinition:
+		name = def.Name
+	case *ast.InputObjectDefinition:
+		name = def.Name
+	case *ast.DirectiveDefinition:
+		if def.Name != nil {
+			return def.Name, "@" + def.Name.Value
+		}
+	}
+	if name == nil {
+		return nil, ""
+	}
+	return name, name.Value
+}
+
+func syntaxDiagnostic(text string, err error) diagnostic {
+	message := err.Error()
+	offset := 0
+	var gqlErr *gqlerrors.Error
+	if errors.As(err, &gqlErr) {
+		if len(gqlErr.Positions) > 0 {
+			offset = gqlErr.Positions[0]
+		}
+		// Syntax errors read "Syntax Error <source> (<line>:<column>) <description>"
+		// followed by the source around the error
+		message, _, _ = strings.Cut(gqlErr.Message, "\n")
+		if _, description, ok := strings.Cut(message, ") "); ok {
+			message = description
+		}
+	}
+	start := positionAt(text, offset)
+	return diagnostic{
+		Range:    textRange{start, start},
+		Severity: severityError,
+		Source:   "graphql-go-gen",
+		Message:  message,
+	}
+}
+
+// wordRange returns the range of the name at a line and character of text, or an
+// empty range if there is none.
+func wordRange(text string, line, character int) textRange {
+	if line < 0 {
+		line, character = 0, 0
+	}
+	start, end := wordAt(text, offsetAt(text, position{line, character}))
+	return textRange{positionAt(text, start), positionAt(text, end)}
+}
+
+// wordAt returns the bounds of the name around offset.
+func wordAt(text string, offset int) (start, end int) {
+	isNameChar := func(c byte) bool {
+		return c == '_' || c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z'
+	}
+	start, end = offset, offset
+	for start > 0 && isNameChar(text[start-1]) {
+		start--
+	}
+	for end < len(text) && isNameChar(text[end]) {
+		end++
+	}
+	return start, end
+}
+
+// reference returns the name of the type or directive, prefixed with @, at a
+// position of a file.
+func (s *Server) reference(params textDocumentPositionParams) string {
+	text, ok := s.files[params.TextDocument.URI]
+	if !ok || s.ctx == nil {
+		return ""
+	}
+	start, end := wordAt(text, offsetAt(text, params.Position))
+	if start == end {
+		return ""
+	}
+	if start > 0 && text[start-1] == '@' {
+		return "@" + text[start:end]
+	}
+	return text[start:end]
+}
+
+func (s *Server) definition(params textDocumentPositionParams) interface{} {
+	name := s.reference(params)
+	if name == "" {
+		return nil
+	}
+	loc := s.ctx.Location(name)
+	if loc == nil || loc.Source == nil {
+		return nil
+	}
+	return locationOf(loc)
+}
+
+func locationOf(loc *ast.Location) location {
+	text := string(loc.Source.Body)
+	return location{
+		URI:   loc.Source.Name,
+		Range: textRange{positionAt(text, loc.Start), positionAt(text, loc.End)},
+	}
+}
+
+func (s *Server) hover(params textDocumentPositionParams) interface{} {
+	name := s.reference(params)
+	if name == "" {
+		return nil
+	}
+	var signature, description string
+	if strings.HasPrefix(name, "@") {
+		directive := s.ctx.Directive(strings.TrimPrefix(name, "@"))
+		if directive == nil {
+			return nil
+		}
+		signature, description = "directive "+name, directive.Description
+	} else {
+		typ, ok := s.ctx.GetObject(name)
+		if !ok {
+			return nil
+		}
+		signature, description = typeKind(typ)+" "+name, typ.Description()
+	}
+	value := fmt.Sprintf("```graphql\n%s\n```", signature)
+	if description != "" {
+		value += "\n\n" + description
+	}
+	return hover{markupContent{"markdown", value}}
+}
+
+func typeKind(typ graphql.Type) string {
+	switch typ.(type) {
+	case *graphql.Object:
+		return "type"
+	case *graphql.Interface:
+		return "interface"
+	case *graphql.Union:
+		return "union"
+	case *graphql.Enum:
+		return "enum"
+	case *graphql.InputObject:
+		return "input"
+	}
+	return "scalar"
+}
+
+// completionKinds are the kinds of completion items by type kind.
+var completionKinds = map[string]int{
+	"type":      completionClass,
+	"interface": completionInterface,
+	"union":     completionClass,
+	"enum":      completionEnum,
+	"input":     completionStruct,
+	"scalar":    completionValue,
+}
+
+func (s *Server) completion() interface{} {
+	items := []completionItem{}
+	add := func(typ graphql.Type) {
+		item := completionItem{Label: typ.Name(), Kind: completionKinds[typeKind(typ)], Detail: typeKind(typ)}
+		if description := typ.Description(); description != "" {
+			item.Documentation = &markupContent{"markdown", description}
+		}
+		items = append(items, item)
+	}
+	for _, scalar := range builtinScalars {
+		add(scalar)
+	}
+	if s.ctx != nil {
+		for _, name := range s.ctx.TypeNames() {
+			typ, _ := s.ctx.GetObject(name)
+			add(typ)
+		}
+	}
+	return items
+}
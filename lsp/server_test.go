@@ -0,0 +1,205 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type testMessage struct {
+	ID     int             `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *responseError  `json:"error"`
+}
+
+// testClient talks to a server. It reads the messages of the server as they are
+// written, so that the server never blocks on diagnostics the test skips.
+type testClient struct {
+	t        *testing.T
+	w        io.Writer
+	messages chan testMessage
+	ids      int
+}
+
+func newTestClient(t *testing.T, w io.Writer, r io.Reader) *testClient {
+	c := &testClient{t: t, w: w, messages: make(chan testMessage, 100)}
+	go func() {
+		defer close(c.messages)
+		r := bufio.NewReader(r)
+		for {
+			body, err := readMessage(r)
+			if err != nil {
+				return
+			}
+			var message testMessage
+			json.Unmarshal(body, &message)
+			c.messages <- message
+		}
+	}()
+	return c
+}
+
+func (c *testClient) send(message map[string]interface{}) {
+	message["jsonrpc"] = "2.0"
+	if err := writeMessage(c.w, message); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// read returns the next message skipping the diagnostics of other files than uri,
+// or all diagnostics if uri is empty.
+func (c *testClient) read(uri string) testMessage {
+	for message := range c.messages {
+		var params publishDiagnosticsParams
+		json.Unmarshal(message.Params, &params)
+		if message.Method == "textDocument/publishDiagnostics" && params.URI != uri {
+			continue
+		}
+		return message
+	}
+	c.t.Fatal("Expected a message")
+	return testMessage{}
+}
+
+func (c *testClient) request(method string, params interface{}, result interface{}) {
+	c.ids++
+	c.send(map[string]interface{}{"id": c.ids, "method": method, "params": params})
+	response := c.read("")
+	if response.ID != c.ids || response.Error != nil {
+		c.t.Fatalf("Expected a response to %s, got %+v", method, response)
+	}
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func (c *testClient) diagnostics(uri string) publishDiagnosticsParams {
+	message := c.read(uri)
+	var params publishDiagnosticsParams
+	if message.Method != "textDocument/publishDiagnostics" {
+		c.t.Fatalf("Expected diagnostics, got %+v", message)
+	}
+	json.Unmarshal(message.Params, &params)
+	return params
+}
+
+func TestServer(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "query.graphql"), []byte("type Query {\n  user: User\n}\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "user.graphql"), []byte("\"A user\"\ntype User {\n  name: String\n}\n"), 0644)
+	queryURI, userURI := pathURI(filepath.Join(dir, "query.graphql")), pathURI(filepath.Join(dir, "user.graphql"))
+
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+	done := make(chan error)
+	go func() {
+		done <- NewServer(inReader, outWriter).Run()
+	}()
+	c := newTestClient(t, inWriter, outReader)
+
+	var initialized map[string]interface{}
+	c.request("initialize", map[string]interface{}{"rootUri": pathURI(dir)}, &initialized)
+	if _, ok := initialized["capabilities"]; !ok {
+		t.Errorf("Expected capabilities, got %v", initialized)
+	}
+
+	text := "\"A user\"\ntype User {\n  name: String\n  friend: Missing\n}\n"
+	c.send(map[string]interface{}{"method": "textDocument/didOpen", "params": map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": userURI, "text": text},
+	}})
+	diagnostics := c.diagnostics(userURI)
+	if diagnostics.URI != userURI || len(diagnostics.Diagnostics) == 0 || !strings.Contains(diagnostics.Diagnostics[0].Message, "Missing") {
+		t.Errorf("Expected a diagnostic for the unknown type Missing, got %+v", diagnostics)
+	}
+
+	text = "\"A user\"\ntype User {\n  name: String\n}\n"
+	c.send(map[string]interface{}{"method": "textDocument/didChange", "params": map[string]interface{}{
+		"textDocument":   map[string]interface{}{"uri": userURI},
+		"contentChanges": []map[string]interface{}{{"text": text}},
+	}})
+	if diagnostics := c.diagnostics(userURI); diagnostics.URI != userURI || len(diagnostics.Diagnostics) != 0 {
+		t.Errorf("Expected the diagnostics to be cleared, got %+v", diagnostics)
+	}
+
+	c.send(map[string]interface{}{"method": "textDocument/didChange", "params": map[string]interface{}{
+		"textDocument":   map[string]interface{}{"uri": userURI},
+		"contentChanges": []map[string]interface{}{{"text": "type User {"}},
+	}})
+	diagnostics = c.diagnostics(userURI)
+	if len(diagnostics.Diagnostics) != 1 || diagnostics.Diagnostics[0].Message != "Expected Name, found EOF" {
+		t.Errorf("Expected a syntax error, got %+v", diagnostics)
+	}
+	c.send(map[string]interface{}{"method": "textDocument/didChange", "params": map[string]interface{}{
+		"textDocument":   map[string]interface{}{"uri": userURI},
+		"contentChanges": []map[string]interface{}{{"text": text}},
+	}})
+	c.diagnostics(userURI)
+
+	userRef := map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": queryURI},
+		"position":     position{1, 9},
+	}
+	var definition location
+	c.request("textDocument/definition", userRef, &definition)
+	expected := location{userURI, textRange{position{0, 0}, position{3, 1}}}
+	if definition != expected {
+		t.Errorf("Expected definition %+v, got %+v", expected, definition)
+	}
+
+	var hovered hover
+	c.request("textDocument/hover", userRef, &hovered)
+	if hovered.Contents.Value != "```graphql\ntype User\n```\n\nA user" {
+		t.Errorf("Expected hover of User, got %q", hovered.Contents.Value)
+	}
+
+	var items []completionItem
+	c.request("textDocument/completion", userRef, &items)
+	var labels []string
+	for _, item := range items {
+		labels = append(labels, item.Label)
+	}
+	if strings.Join(labels, ",") != "Int,Float,String,Boolean,ID,Query,User" {
+		t.Errorf("Expected the built-in scalars and the types as completions, got %v", labels)
+	}
+
+	conflicting := "type Query {\n  user: User\n}\ntype Query { a: Int }\ntype User { a: Int }\n"
+	c.send(map[string]interface{}{"method": "textDocument/didChange", "params": map[string]interface{}{
+		"textDocument":   map[string]interface{}{"uri": queryURI},
+		"contentChanges": []map[string]interface{}{{"text": conflicting}},
+	}})
+	var messages []string
+	for _, diagnostic := range c.diagnostics(queryURI).Diagnostics {
+		messages = append(messages, fmt.Sprint(diagnostic.Range.Start, diagnostic.Message))
+	}
+	if expected := "{0 5}Query is defined 2 times.,{3 5}Query is defined 2 times.,{4 5}User is defined 2 times."; strings.Join(messages, ",") != expected {
+		t.Errorf("Expected %s, got %s", expected, strings.Join(messages, ","))
+	}
+	diagnostics = c.diagnostics(userURI)
+	if len(diagnostics.Diagnostics) != 1 || diagnostics.Diagnostics[0].Range != (textRange{position{1, 5}, position{1, 9}}) {
+		t.Errorf("Expected the conflicting definition of User, got %+v", diagnostics)
+	}
+	c.send(map[string]interface{}{"method": "textDocument/didChange", "params": map[string]interface{}{
+		"textDocument":   map[string]interface{}{"uri": queryURI},
+		"contentChanges": []map[string]interface{}{{"text": "type Query {\n  user: User\n}\n"}},
+	}})
+	if diagnostics := c.diagnostics(queryURI); len(diagnostics.Diagnostics) != 0 {
+		t.Errorf("Expected the conflicts to be cleared, got %+v", diagnostics)
+	}
+	if diagnostics := c.diagnostics(userURI); len(diagnostics.Diagnostics) != 0 {
+		t.Errorf("Expected the conflicts to be cleared, got %+v", diagnostics)
+	}
+
+	var result interface{}
+	c.request("shutdown", nil, &result)
+	c.send(map[string]interface{}{"method": "exit"})
+	if err := <-done; err != nil {
+		t.Errorf("Expected the server to exit cleanly, got %s", err)
+	}
+}